@@ -0,0 +1,110 @@
+// пакет с api
+package api
+
+import (
+	"common"
+	"encoding/json"
+	"lru"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// структура запроса на изменение счётчика
+type incrRequest struct {
+	Delta      *int64 `json:"delta"`
+	TTLseconds int    `json:"ttl_seconds"`
+}
+
+// структура ответа метода на изменение счётчика
+type incrResponse struct {
+	baseResponse
+	Key   string `json:"key"`
+	Value int64  `json:"value"`
+}
+
+// incrHandler HTTP-обработчик для увеличения счётчика, delta по умолчанию равна 1 и может быть отрицательной.
+// TTL применяется только при создании ключа. С заголовками If-Match и If-None-Match счётчик меняется,
+// только если условие соблюдено, иначе 412.
+func (h *CacheHandler) incrHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reqData := incrRequest{}
+	resp := incrResponse{}
+
+	counterCache, ok := h.cache.(lru.ICounterCache)
+	if !ok {
+		log.Error("cache does not support counters")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	key := mux.Vars(r)["key"]
+	if !common.ValidString(key) {
+		log.Error("key is empty")
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&reqData)
+		if err != nil {
+			log.Errorf("failed to decode incr rq body with error [%s]", err.Error())
+			resp.SetError(errWrongParams)
+			writeResponse(w, resp, http.StatusBadRequest)
+			return
+		}
+	}
+
+	delta := int64(1)
+	if reqData.Delta != nil {
+		delta = *reqData.Delta
+	}
+	var ttl time.Duration
+	if reqData.TTLseconds > 0 {
+		ttl = time.Second * time.Duration(reqData.TTLseconds)
+	}
+
+	cond, conditional, err := parsePrecondition(r)
+	var condCache lru.IConditionalCache
+	if err == nil {
+		condCache, err = h.conditionalCache(conditional)
+	}
+	var value int64
+	switch {
+	case err != nil:
+	case condCache != nil:
+		value, err = condCache.IncrIf(ctx, key, delta, ttl, cond)
+		err = preconditionError(err)
+	default:
+		value, err = counterCache.Incr(ctx, key, delta, ttl)
+	}
+	if writePreconditionError(w, &resp, key, err) {
+		return
+	}
+	if err != nil {
+		log.Errorf("failed to incr key [%s] with error [%s]", key, err.Error())
+		switch err.Error() {
+		case lru.ErrNotNumeric:
+			resp.SetError(errNotNumeric)
+			writeResponse(w, resp, http.StatusConflict)
+		case lru.ErrOverflow:
+			resp.SetError(errOverflow)
+			writeResponse(w, resp, http.StatusConflict)
+		default:
+			resp.SetError(errInternal)
+			writeResponse(w, resp, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp.Key = key
+	resp.Value = value
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
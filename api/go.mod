@@ -1,6 +1,6 @@
 module api
 
-go 1.22
+go 1.24
 
 require lru v0.0.0-00010101000000-000000000000
 
@@ -3,7 +3,9 @@ package api
 
 import (
 	"common"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"lru"
 	"net/http"
@@ -15,11 +17,20 @@ import (
 
 // errors
 const (
-	errInternal    = "INTERNAL_ERROR"
-	errWrongParams = "WRONG_PARAMS"
-	errNotFound    = "NOT_FOUND"
+	errInternal     = "INTERNAL_ERROR"
+	errWrongParams  = "WRONG_PARAMS"
+	errNotFound     = "NOT_FOUND"
+	errTooLarge     = "ENTRY_TOO_LARGE"
+	errNotSupported = "NOT_SUPPORTED"
+	errKnownAbsent  = "KNOWN_ABSENT"
+	errPrecondition = "PRECONDITION_FAILED"
+	errNotNumeric   = "VALUE_NOT_NUMERIC"
+	errOverflow     = "INTEGER_OVERFLOW"
 )
 
+// ошибка вызова операции, которую кеш не поддерживает
+var errNotSupportedByCache = errors.New("operation is not supported by cache")
+
 // базовая структура ответа, содержится во всех структурах ответа
 type baseResponse struct {
 	Success bool   `json:"success"`
@@ -51,6 +62,14 @@ func writeResponse(w http.ResponseWriter, response interface{}, statusCode int)
 	io.WriteString(w, string(byteBody))
 }
 
+// время истечения в секундах unix, 0 для бессрочной записи
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
 // структура ответа метода api/Ping
 type pingResponse struct {
 	baseResponse
@@ -66,25 +85,32 @@ func pingHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, resp, http.StatusOK)
 }
 
-// CacheHandler содержит LRU-кеш и методы для работы с ним
+// CacheHandler содержит LRU-кеш и методы для работы с ним.
+// TTL по умолчанию и прочие настройки сроков действия задаются при создании кеша.
 type CacheHandler struct {
-	cache           lru.ILRUCache
-	defaultCacheTTL time.Duration
+	cache         lru.ILRUCache
+	refreshOrigin string // адрес источника для упреждающего обновления, пустой отключает его
 }
 
 // конструктор CacheHandler
-func NewCacheHandler(cache lru.ILRUCache, defaultCacheTTL time.Duration) *CacheHandler {
+func NewCacheHandler(cache lru.ILRUCache, refreshOrigin string) *CacheHandler {
 	return &CacheHandler{
-		cache:           cache,
-		defaultCacheTTL: defaultCacheTTL,
+		cache:         cache,
+		refreshOrigin: refreshOrigin,
 	}
 }
 
 // структура запроса на добавление данных
 type addDataRequest struct {
-	Key        string      `json:"key"`
-	Value      interface{} `json:"value"`
-	TTLseconds int         `json:"ttl_seconds"`
+	Key                string      `json:"key"`
+	Value              interface{} `json:"value"`
+	TTLseconds         int         `json:"ttl_seconds"`
+	GraceSeconds       int         `json:"grace_seconds"`
+	Negative           bool        `json:"negative"`
+	Tags               []string    `json:"tags"`
+	Sliding            bool        `json:"sliding"`
+	MaxLifetimeSeconds int         `json:"max_lifetime_seconds"`
+	TTIseconds         int         `json:"tti_seconds"`
 }
 
 // структура ответа метода на добавление данных
@@ -108,12 +134,46 @@ func (h *CacheHandler) putHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ttl := h.defaultCacheTTL
+	var ttl time.Duration
 	if reqData.TTLseconds > 0 {
 		ttl = time.Second * time.Duration(reqData.TTLseconds)
 	}
 
-	err = h.cache.Put(ctx, reqData.Key, reqData.Value, ttl)
+	var opts []lru.PutOption
+	if reqData.GraceSeconds > 0 {
+		opts = append(opts, lru.WithGracePeriod(time.Second*time.Duration(reqData.GraceSeconds)))
+	}
+	if len(reqData.Tags) > 0 {
+		opts = append(opts, lru.WithTags(reqData.Tags...))
+	}
+	if reqData.Sliding {
+		opts = append(opts, lru.WithSliding(time.Second*time.Duration(reqData.MaxLifetimeSeconds)))
+	}
+	if reqData.TTIseconds > 0 {
+		opts = append(opts, lru.WithTTI(time.Second*time.Duration(reqData.TTIseconds)))
+	}
+
+	cond, conditional, err := parsePrecondition(r)
+
+	var version uint64
+	switch {
+	case err != nil:
+	case conditional && reqData.Negative:
+		err = errBadPrecondition
+	case reqData.Negative:
+		err = h.putNegative(ctx, reqData.Key, ttl)
+	default:
+		version, err = h.put(ctx, reqData.Key, reqData.Value, ttl, opts, cond, conditional)
+	}
+	if writePreconditionError(w, &resp, reqData.Key, err) {
+		return
+	}
+	if err != nil && err.Error() == lru.ErrEntryTooLarge {
+		log.Errorf("failed to put data by key [%s] with error [%s]", reqData.Key, err.Error())
+		resp.SetError(errTooLarge)
+		writeResponse(w, resp, http.StatusRequestEntityTooLarge)
+		return
+	}
 	if err != nil {
 		log.Errorf("failed to put data in cache with error [%s]", err.Error())
 		resp.SetError(errInternal)
@@ -121,16 +181,52 @@ func (h *CacheHandler) putHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if version > 0 {
+		w.Header().Set("ETag", formatETag(version))
+	}
 	resp.SetSuccess()
 	writeResponse(w, resp, http.StatusCreated)
 }
 
+// запись в кеш с условием и дополнительными параметрами, если они заданы и кеш их поддерживает.
+// Возвращает версию записанного значения, 0 - если кеш не поддерживает версии.
+func (h *CacheHandler) put(ctx context.Context, key string, value interface{}, ttl time.Duration, opts []lru.PutOption, cond lru.Precondition, conditional bool) (uint64, error) {
+	condCache, err := h.conditionalCache(conditional)
+	if err != nil {
+		return 0, err
+	}
+	if condCache != nil {
+		version, err := condCache.PutIf(ctx, key, value, ttl, cond, opts...)
+		return version, preconditionError(err)
+	}
+
+	if len(opts) == 0 {
+		return 0, h.cache.Put(ctx, key, value, ttl)
+	}
+	entryCache, ok := h.cache.(lru.IEntryCache)
+	if !ok {
+		return 0, errNotSupportedByCache
+	}
+	return 0, entryCache.PutWithOptions(ctx, key, value, ttl, opts...)
+}
+
+// запись в кеш факта отсутствия ключа, если кеш это поддерживает
+func (h *CacheHandler) putNegative(ctx context.Context, key string, ttl time.Duration) error {
+	negativeCache, ok := h.cache.(lru.INegativeCache)
+	if !ok {
+		return errNotSupportedByCache
+	}
+	return negativeCache.PutNegative(ctx, key, ttl)
+}
+
 // структура ответа метода на получение одного элемента
 type getDataResponse struct {
 	baseResponse
 	Key       string      `json:"key"`
 	Value     interface{} `json:"value"`
 	ExpiresAt int64       `json:"expires_at"`
+	Stale     bool        `json:"stale"`
+	Negative  bool        `json:"negative,omitempty"`
 }
 
 // getHandler HTTP-обработчик для получения элемента из кеша
@@ -149,7 +245,16 @@ func (h *CacheHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	value, expiresAt, err := h.cache.Get(ctx, key)
+	entry, err := h.get(ctx, key)
+	if err != nil && err.Error() == lru.ErrKeyNegative {
+		log.Infof("key [%s] is known to be absent", key)
+		resp.Key = key
+		resp.ExpiresAt = unixTime(entry.ExpiresAt)
+		resp.Negative = true
+		resp.SetError(errKnownAbsent)
+		writeResponse(w, resp, http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		log.Errorf("failed to get data by key [%s] with error [%s]", key, err.Error())
 		resp.SetError(errNotFound)
@@ -158,13 +263,26 @@ func (h *CacheHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp.Key = key
-	resp.Value = value
-	resp.ExpiresAt = expiresAt.Unix()
+	resp.Value = entry.Value
+	resp.ExpiresAt = unixTime(entry.ExpiresAt)
+	resp.Stale = entry.Stale
 
+	if entry.Version > 0 {
+		w.Header().Set("ETag", formatETag(entry.Version))
+	}
 	resp.SetSuccess()
 	writeResponse(w, resp, http.StatusOK)
 }
 
+// чтение записи из кеша, с признаком устаревания, если кеш его поддерживает
+func (h *CacheHandler) get(ctx context.Context, key string) (lru.Entry[interface{}], error) {
+	if entryCache, ok := h.cache.(lru.IEntryCache); ok {
+		return entryCache.GetEntry(ctx, key)
+	}
+	value, expiresAt, err := h.cache.Get(ctx, key)
+	return lru.Entry[interface{}]{Value: value, ExpiresAt: expiresAt}, err
+}
+
 // структура ответа метода на получение всех элементов
 type getAllDataResponse struct {
 	baseResponse
@@ -172,10 +290,16 @@ type getAllDataResponse struct {
 	Values []interface{} `json:"values"`
 }
 
-// getAllHandler HTTP-обработчик для получения всех элементов из кеша
+// getAllHandler HTTP-обработчик для получения всех элементов из кеша.
+// С параметром prefix возвращает только ключи с этим префиксом.
 func (h *CacheHandler) getAllHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if r.URL.Query().Has("prefix") {
+		h.keysWithPrefixHandler(w, r)
+		return
+	}
+
 	resp := getAllDataResponse{}
 
 	keys, values, err := h.cache.GetAll(ctx)
@@ -205,7 +329,8 @@ type evictDataResponse struct {
 	baseResponse
 }
 
-// evictHandler HTTP-обработчик для удаления элемента из кеша
+// evictHandler HTTP-обработчик для удаления элемента из кеша.
+// С заголовками If-Match и If-None-Match элемент удаляется, только если условие соблюдено, иначе 412.
 func (h *CacheHandler) evictHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -221,7 +346,13 @@ func (h *CacheHandler) evictHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := h.cache.Evict(ctx, key)
+	cond, conditional, err := parsePrecondition(r)
+	if err == nil {
+		err = h.evict(ctx, key, cond, conditional)
+	}
+	if writePreconditionError(w, &resp, key, err) {
+		return
+	}
 	if err != nil {
 		log.Errorf("failed to evict data by key [%s] with error [%s]", key, err.Error())
 		resp.SetError(errNotFound)
@@ -233,15 +364,35 @@ func (h *CacheHandler) evictHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, resp, http.StatusNoContent)
 }
 
+// удаление элемента из кеша с условием, если оно задано
+func (h *CacheHandler) evict(ctx context.Context, key string, cond lru.Precondition, conditional bool) error {
+	if !conditional {
+		_, err := h.cache.Evict(ctx, key)
+		return err
+	}
+	condCache, err := h.conditionalCache(conditional)
+	if err != nil {
+		return err
+	}
+	_, err = condCache.EvictIf(ctx, key, cond)
+	return preconditionError(err)
+}
+
 // структура ответа метода на удаление всех элементов
 type evictAllDataResponse struct {
 	baseResponse
 }
 
-// evictAllHandler HTTP-обработчик для очистки кеша
+// evictAllHandler HTTP-обработчик для очистки кеша.
+// С параметром prefix удаляет только элементы с ключами с этим префиксом.
 func (h *CacheHandler) evictAllHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if r.URL.Query().Has("prefix") {
+		h.evictPrefixHandler(w, r)
+		return
+	}
+
 	resp := evictAllDataResponse{}
 
 	err := h.cache.EvictAll(ctx)
@@ -255,3 +406,328 @@ func (h *CacheHandler) evictAllHandler(w http.ResponseWriter, r *http.Request) {
 	resp.SetSuccess()
 	writeResponse(w, resp, http.StatusNoContent)
 }
+
+// структура ответа метода на получение статистики кеша
+type statsResponse struct {
+	baseResponse
+	Hits              uint64  `json:"hits"`
+	Misses            uint64  `json:"misses"`
+	Expired           uint64  `json:"expired"`
+	CapacityEvictions uint64  `json:"capacity_evictions"`
+	ManualEvictions   uint64  `json:"manual_evictions"`
+	Len               int     `json:"len"`
+	Capacity          int     `json:"capacity"`
+	HitRatio          float64 `json:"hit_ratio"`
+}
+
+// statsHandler HTTP-обработчик для получения статистики кеша
+func (h *CacheHandler) statsHandler(w http.ResponseWriter, r *http.Request) {
+	resp := statsResponse{}
+
+	statsCache, ok := h.cache.(lru.IStatsCache)
+	if !ok {
+		log.Error("cache does not collect stats")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	stats := statsCache.Stats()
+	resp.Hits = stats.Hits
+	resp.Misses = stats.Misses
+	resp.Expired = stats.Expired
+	resp.CapacityEvictions = stats.CapacityEvictions
+	resp.ManualEvictions = stats.ManualEvictions
+	resp.Len = stats.Len
+	resp.Capacity = stats.Capacity
+	resp.HitRatio = stats.HitRatio()
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// структура запроса на пакетное добавление данных
+type batchPutRequest struct {
+	Items []batchPutItem `json:"items"`
+}
+
+// элемент пакетного добавления данных
+type batchPutItem struct {
+	Key        string      `json:"key"`
+	Value      interface{} `json:"value"`
+	TTLseconds int         `json:"ttl_seconds"`
+}
+
+// структура запроса на пакетное получение или удаление данных
+type batchKeysRequest struct {
+	Keys []string `json:"keys"`
+}
+
+// результат обработки одного элемента пакета
+type batchItemResult struct {
+	Key       string      `json:"key"`
+	Status    int         `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+	ExpiresAt int64       `json:"expires_at,omitempty"`
+}
+
+// структура ответа пакетных методов, результаты располагаются в порядке элементов запроса
+type batchResponse struct {
+	baseResponse
+	Results []batchItemResult `json:"results"`
+}
+
+// разбор тела пакетного запроса и проверка поддержки пакетных операций кешем.
+// При ошибке ответ уже записан и возвращается false.
+func (h *CacheHandler) batchCache(w http.ResponseWriter, r *http.Request, reqData interface{}) (lru.IBatchCache, bool) {
+	resp := batchResponse{}
+
+	batchCache, ok := h.cache.(lru.IBatchCache)
+	if !ok {
+		log.Error("cache does not support batch operations")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return nil, false
+	}
+
+	err := json.NewDecoder(r.Body).Decode(reqData)
+	if err != nil {
+		log.Errorf("failed to decode batch rq body with error [%s]", err.Error())
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return nil, false
+	}
+	return batchCache, true
+}
+
+// заполнение результатов для пустых ключей, возвращает позиции корректных ключей
+func validBatchKeys(keys []string, results []batchItemResult) []int {
+	positions := make([]int, 0, len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+		if !common.ValidString(key) {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = errWrongParams
+			continue
+		}
+		positions = append(positions, i)
+	}
+	return positions
+}
+
+// batchPutHandler HTTP-обработчик для пакетного добавления элементов в кеш
+func (h *CacheHandler) batchPutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reqData := batchPutRequest{}
+	batchCache, ok := h.batchCache(w, r, &reqData)
+	if !ok {
+		return
+	}
+
+	keys := make([]string, len(reqData.Items))
+	for i, item := range reqData.Items {
+		keys[i] = item.Key
+	}
+	results := make([]batchItemResult, len(keys))
+	positions := validBatchKeys(keys, results)
+
+	items := make([]lru.BatchItem[string, interface{}], len(positions))
+	for i, pos := range positions {
+		var ttl time.Duration
+		if reqData.Items[pos].TTLseconds > 0 {
+			ttl = time.Second * time.Duration(reqData.Items[pos].TTLseconds)
+		}
+		items[i] = lru.BatchItem[string, interface{}]{Key: keys[pos], Value: reqData.Items[pos].Value, TTL: ttl}
+	}
+
+	for i, err := range batchCache.PutMany(ctx, items) {
+		result := &results[positions[i]]
+		switch {
+		case err == nil:
+			result.Status = http.StatusCreated
+		case err.Error() == lru.ErrEntryTooLarge:
+			result.Status = http.StatusRequestEntityTooLarge
+			result.Error = errTooLarge
+		default:
+			log.Errorf("failed to put data by key [%s] with error [%s]", result.Key, err.Error())
+			result.Status = http.StatusInternalServerError
+			result.Error = errInternal
+		}
+	}
+
+	resp := batchResponse{Results: results}
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// batchGetHandler HTTP-обработчик для пакетного получения элементов из кеша
+func (h *CacheHandler) batchGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reqData := batchKeysRequest{}
+	batchCache, ok := h.batchCache(w, r, &reqData)
+	if !ok {
+		return
+	}
+
+	results := make([]batchItemResult, len(reqData.Keys))
+	positions := validBatchKeys(reqData.Keys, results)
+
+	keys := make([]string, len(positions))
+	for i, pos := range positions {
+		keys[i] = reqData.Keys[pos]
+	}
+
+	entries, errs := batchCache.GetMany(ctx, keys)
+	for i, err := range errs {
+		result := &results[positions[i]]
+		switch {
+		case err == nil:
+			result.Status = http.StatusOK
+			result.Value = entries[i].Value
+			result.ExpiresAt = unixTime(entries[i].ExpiresAt)
+		case err.Error() == lru.ErrKeyNegative:
+			result.Status = http.StatusNotFound
+			result.Error = errKnownAbsent
+		default:
+			result.Status = http.StatusNotFound
+			result.Error = errNotFound
+		}
+	}
+
+	resp := batchResponse{Results: results}
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// batchEvictHandler HTTP-обработчик для пакетного удаления элементов из кеша
+func (h *CacheHandler) batchEvictHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reqData := batchKeysRequest{}
+	batchCache, ok := h.batchCache(w, r, &reqData)
+	if !ok {
+		return
+	}
+
+	results := make([]batchItemResult, len(reqData.Keys))
+	positions := validBatchKeys(reqData.Keys, results)
+
+	keys := make([]string, len(positions))
+	for i, pos := range positions {
+		keys[i] = reqData.Keys[pos]
+	}
+
+	_, errs := batchCache.EvictMany(ctx, keys)
+	for i, err := range errs {
+		result := &results[positions[i]]
+		if err != nil {
+			result.Status = http.StatusNotFound
+			result.Error = errNotFound
+			continue
+		}
+		result.Status = http.StatusNoContent
+	}
+
+	resp := batchResponse{Results: results}
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// структура ответа методов группового удаления элементов
+type evictGroupResponse struct {
+	baseResponse
+	Evicted int `json:"evicted"`
+}
+
+// evictByTagHandler HTTP-обработчик для удаления всех элементов с тегом
+func (h *CacheHandler) evictByTagHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := evictGroupResponse{}
+
+	tagCache, ok := h.cache.(lru.ITagCache)
+	if !ok {
+		log.Error("cache does not support tags")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	tag := mux.Vars(r)["tag"]
+	if !common.ValidString(tag) {
+		log.Error("tag is empty")
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	resp.Evicted = tagCache.EvictByTag(ctx, tag)
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// структура ответа метода на получение ключей по префиксу
+type keysWithPrefixResponse struct {
+	baseResponse
+	Keys []string `json:"keys"`
+}
+
+// keysWithPrefixHandler HTTP-обработчик для получения ключей с префиксом в лексикографическом порядке
+func (h *CacheHandler) keysWithPrefixHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := keysWithPrefixResponse{}
+
+	prefixCache, ok := h.cache.(lru.IPrefixCache)
+	if !ok {
+		log.Error("cache does not support prefix operations")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	resp.Keys = prefixCache.KeysWithPrefix(ctx, r.URL.Query().Get("prefix"))
+
+	resp.SetSuccess()
+
+	if len(resp.Keys) == 0 {
+		log.Info("no keys with prefix")
+		writeResponse(w, resp, http.StatusNoContent)
+		return
+	}
+
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// evictPrefixHandler HTTP-обработчик для удаления всех элементов с префиксом ключа.
+// Пустой префикс не принимается, для очистки кеша используется запрос без параметра.
+func (h *CacheHandler) evictPrefixHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := evictGroupResponse{}
+
+	prefixCache, ok := h.cache.(lru.IPrefixCache)
+	if !ok {
+		log.Error("cache does not support prefix operations")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	if !common.ValidString(prefix) {
+		log.Error("prefix is empty")
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	resp.Evicted = prefixCache.EvictPrefix(ctx, prefix)
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
@@ -0,0 +1,104 @@
+// пакет с api
+package api
+
+import (
+	"common"
+	"lru"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// peekHandler HTTP-обработчик для получения элемента из кеша без изменения порядка вытеснения
+func (h *CacheHandler) peekHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := getDataResponse{}
+
+	inspectCache, ok := h.cache.(lru.IInspectCache)
+	if !ok {
+		log.Error("cache does not support inspection")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	key := mux.Vars(r)["key"]
+	if !common.ValidString(key) {
+		log.Error("key is empty")
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	value, expiresAt, err := inspectCache.Peek(ctx, key)
+	if err != nil {
+		log.Errorf("failed to peek data by key [%s] with error [%s]", key, err.Error())
+		resp.SetError(errNotFound)
+		writeResponse(w, resp, http.StatusNotFound)
+		return
+	}
+
+	resp.Key = key
+	resp.Value = value
+	resp.ExpiresAt = unixTime(expiresAt)
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// containsHandler HTTP-обработчик проверки наличия элемента в кеше, отвечает без тела
+func (h *CacheHandler) containsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	inspectCache, ok := h.cache.(lru.IInspectCache)
+	if !ok {
+		log.Error("cache does not support inspection")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	key := mux.Vars(r)["key"]
+	if !common.ValidString(key) {
+		log.Error("key is empty")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !inspectCache.Contains(ctx, key) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// структура ответа метода на получение списка ключей
+type keysResponse struct {
+	baseResponse
+	Keys     []string `json:"keys"`
+	Len      int      `json:"len"`
+	Capacity int      `json:"capacity"`
+}
+
+// keysHandler HTTP-обработчик для получения ключей от недавно использованных к давно использованным
+func (h *CacheHandler) keysHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := keysResponse{}
+
+	inspectCache, ok := h.cache.(lru.IInspectCache)
+	if !ok {
+		log.Error("cache does not support inspection")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	resp.Keys = inspectCache.Keys(ctx)
+	resp.Len = inspectCache.Len()
+	resp.Capacity = inspectCache.Capacity()
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
@@ -0,0 +1,134 @@
+// пакет с api
+package api
+
+import (
+	"common"
+	"context"
+	"encoding/json"
+	"fmt"
+	"lru"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// HTTP-клиент для загрузки значений из источника при упреждающем обновлении
+var originClient = &http.Client{Timeout: 10 * time.Second}
+
+// структура запроса на включение упреждающего обновления для префикса ключей
+type refreshRuleRequest struct {
+	Prefix     string  `json:"prefix"`
+	Threshold  float64 `json:"threshold"`
+	TTLseconds int     `json:"ttl_seconds"`
+}
+
+// структура ответа метода на включение упреждающего обновления
+type refreshRuleResponse struct {
+	baseResponse
+}
+
+// ф-я загрузки значения ключа из источника: GET-запрос на originURL с экранированным ключом,
+// тело ответа в формате JSON становится значением
+func originLoader(originURL string, ttl time.Duration) lru.KeyLoaderFunc[string, interface{}] {
+	return func(ctx context.Context, key string) (interface{}, time.Duration, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, originURL+url.PathEscape(key), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		resp, err := originClient.Do(req)
+		if err != nil {
+			log.Errorf("failed to load key [%s] from origin with error [%s]", key, err.Error())
+			return nil, 0, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			log.Errorf("failed to load key [%s] from origin with status [%d]", key, resp.StatusCode)
+			return nil, 0, fmt.Errorf("origin responded with status [%d]", resp.StatusCode)
+		}
+
+		var value interface{}
+		err = json.NewDecoder(resp.Body).Decode(&value)
+		if err != nil {
+			log.Errorf("failed to decode origin value for key [%s] with error [%s]", key, err.Error())
+			return nil, 0, err
+		}
+		return value, ttl, nil
+	}
+}
+
+// setRefreshHandler HTTP-обработчик для включения упреждающего обновления ключей с префиксом
+func (h *CacheHandler) setRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	reqData := refreshRuleRequest{}
+	resp := refreshRuleResponse{}
+
+	refreshCache, ok := h.cache.(lru.IRefreshCache)
+	if !ok || h.refreshOrigin == "" {
+		log.Error("cache does not support refresh-ahead or refresh origin is not configured")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&reqData)
+	if err != nil {
+		log.Errorf("failed to decode refresh rq body with error [%s]", err.Error())
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	if !common.ValidString(reqData.Prefix) || reqData.Threshold <= 0 || reqData.Threshold > 1 {
+		log.Errorf("wrong refresh rule prefix [%s] or threshold [%f]", reqData.Prefix, reqData.Threshold)
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	var ttl time.Duration
+	if reqData.TTLseconds > 0 {
+		ttl = time.Second * time.Duration(reqData.TTLseconds)
+	}
+
+	prefix := reqData.Prefix
+	refreshCache.SetRefreshAhead(prefix, lru.RefreshRule[string, interface{}]{
+		Match: func(key string) bool {
+			return strings.HasPrefix(key, prefix)
+		},
+		Loader:    originLoader(h.refreshOrigin, ttl),
+		Threshold: reqData.Threshold,
+	})
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusCreated)
+}
+
+// removeRefreshHandler HTTP-обработчик для отключения упреждающего обновления ключей с префиксом
+func (h *CacheHandler) removeRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	resp := refreshRuleResponse{}
+
+	refreshCache, ok := h.cache.(lru.IRefreshCache)
+	if !ok {
+		log.Error("cache does not support refresh-ahead")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return
+	}
+
+	prefix := mux.Vars(r)["prefix"]
+	if !refreshCache.RemoveRefreshAhead(prefix) {
+		log.Errorf("refresh rule for prefix [%s] not found", prefix)
+		resp.SetError(errNotFound)
+		writeResponse(w, resp, http.StatusNotFound)
+		return
+	}
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusNoContent)
+}
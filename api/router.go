@@ -29,6 +29,19 @@ func NewRouter(ch *CacheHandler) *mux.Router {
 		{Name: "GetAll", Method: http.MethodGet, Pattern: "/api/lru", HandlerFunc: ch.getAllHandler, MiddlewareAuthFunc: logMiddleware},
 		{Name: "Evict", Method: http.MethodDelete, Pattern: "/api/lru/{key}", HandlerFunc: ch.evictHandler, MiddlewareAuthFunc: logMiddleware},
 		{Name: "EvictAll", Method: http.MethodDelete, Pattern: "/api/lru", HandlerFunc: ch.evictAllHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "EvictByTag", Method: http.MethodDelete, Pattern: "/api/lru/tags/{tag}", HandlerFunc: ch.evictByTagHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "GetTTL", Method: http.MethodGet, Pattern: "/api/lru/{key}/ttl", HandlerFunc: ch.getTTLHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "SetTTL", Method: http.MethodPatch, Pattern: "/api/lru/{key}/ttl", HandlerFunc: ch.setTTLHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "Incr", Method: http.MethodPost, Pattern: "/api/lru/{key}/incr", HandlerFunc: ch.incrHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "BatchPut", Method: http.MethodPost, Pattern: "/api/lru/batch", HandlerFunc: ch.batchPutHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "BatchGet", Method: http.MethodPost, Pattern: "/api/lru/batch/get", HandlerFunc: ch.batchGetHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "BatchEvict", Method: http.MethodPost, Pattern: "/api/lru/batch/delete", HandlerFunc: ch.batchEvictHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "SetRefresh", Method: http.MethodPost, Pattern: "/api/refresh", HandlerFunc: ch.setRefreshHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "RemoveRefresh", Method: http.MethodDelete, Pattern: "/api/refresh/{prefix}", HandlerFunc: ch.removeRefreshHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "Contains", Method: http.MethodHead, Pattern: "/api/lru/{key}", HandlerFunc: ch.containsHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "Peek", Method: http.MethodGet, Pattern: "/api/peek/{key}", HandlerFunc: ch.peekHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "Keys", Method: http.MethodGet, Pattern: "/api/keys", HandlerFunc: ch.keysHandler, MiddlewareAuthFunc: logMiddleware},
+		{Name: "Stats", Method: http.MethodGet, Pattern: "/api/stats", HandlerFunc: ch.statsHandler, MiddlewareAuthFunc: logMiddleware},
 	}
 
 	router := mux.NewRouter().StrictSlash(false)
@@ -0,0 +1,151 @@
+// пакет с api
+package api
+
+import (
+	"common"
+	"encoding/json"
+	"lru"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// структура ответа метода на получение срока действия элемента
+type ttlResponse struct {
+	baseResponse
+	Key        string `json:"key"`
+	TTLseconds int64  `json:"ttl_seconds"` // -1 для бессрочного элемента
+	ExpiresAt  int64  `json:"expires_at"`  // 0 для бессрочного элемента
+}
+
+// структура запроса на изменение срока действия элемента, задаётся ровно одно из полей
+type ttlRequest struct {
+	TTLseconds *int   `json:"ttl_seconds"`
+	ExpiresAt  *int64 `json:"expires_at"`
+	Persist    bool   `json:"persist"`
+}
+
+// структура ответа метода на изменение срока действия элемента
+type setTTLResponse struct {
+	baseResponse
+}
+
+// получение кеша с управлением сроком действия и ключа из запроса. При ошибке ответ уже записан и возвращается false.
+func (h *CacheHandler) ttlCache(w http.ResponseWriter, r *http.Request, resp interface{ SetError(string) }) (lru.ITTLCache, string, bool) {
+	ttlCache, ok := h.cache.(lru.ITTLCache)
+	if !ok {
+		log.Error("cache does not support ttl management")
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+		return nil, "", false
+	}
+
+	key := mux.Vars(r)["key"]
+	if !common.ValidString(key) {
+		log.Error("key is empty")
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return nil, "", false
+	}
+	return ttlCache, key, true
+}
+
+// getTTLHandler HTTP-обработчик для получения оставшегося срока действия элемента
+func (h *CacheHandler) getTTLHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := ttlResponse{}
+
+	ttlCache, key, ok := h.ttlCache(w, r, &resp)
+	if !ok {
+		return
+	}
+
+	ttl, err := ttlCache.TTL(ctx, key)
+	if err != nil {
+		log.Errorf("failed to get ttl by key [%s] with error [%s]", key, err.Error())
+		resp.SetError(errNotFound)
+		writeResponse(w, resp, http.StatusNotFound)
+		return
+	}
+
+	resp.Key = key
+	resp.TTLseconds = -1
+	if ttl != lru.NoExpiration {
+		resp.TTLseconds = int64(math.Ceil(ttl.Seconds()))
+		resp.ExpiresAt = time.Now().Add(ttl).Unix()
+	}
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusOK)
+}
+
+// setTTLHandler HTTP-обработчик для изменения срока действия элемента без перезаписи значения.
+// С заголовками If-Match и If-None-Match срок меняется, только если условие соблюдено, иначе 412.
+func (h *CacheHandler) setTTLHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reqData := ttlRequest{}
+	resp := setTTLResponse{}
+
+	ttlCache, key, ok := h.ttlCache(w, r, &resp)
+	if !ok {
+		return
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&reqData)
+	if err != nil {
+		log.Errorf("failed to decode ttl rq body with error [%s]", err.Error())
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	// нулевое время истечения делает элемент бессрочным
+	var ttl time.Duration
+	var expiresAt time.Time
+	switch {
+	case reqData.TTLseconds != nil && reqData.ExpiresAt == nil && !reqData.Persist && *reqData.TTLseconds > 0:
+		ttl = time.Second * time.Duration(*reqData.TTLseconds)
+	case reqData.ExpiresAt != nil && reqData.TTLseconds == nil && !reqData.Persist && *reqData.ExpiresAt > 0:
+		expiresAt = time.Unix(*reqData.ExpiresAt, 0)
+	case reqData.Persist && reqData.TTLseconds == nil && reqData.ExpiresAt == nil:
+	default:
+		log.Errorf("wrong ttl params for key [%s]", key)
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+		return
+	}
+
+	cond, conditional, err := parsePrecondition(r)
+	var condCache lru.IConditionalCache
+	if err == nil {
+		condCache, err = h.conditionalCache(conditional)
+	}
+	switch {
+	case err != nil:
+	case condCache != nil && ttl > 0:
+		err = preconditionError(condCache.TouchIf(ctx, key, ttl, cond))
+	case condCache != nil:
+		err = preconditionError(condCache.ExpireAtIf(ctx, key, expiresAt, cond))
+	case ttl > 0:
+		err = ttlCache.Touch(ctx, key, ttl)
+	default:
+		err = ttlCache.ExpireAt(ctx, key, expiresAt)
+	}
+	if writePreconditionError(w, &resp, key, err) {
+		return
+	}
+	if err != nil {
+		log.Errorf("failed to set ttl by key [%s] with error [%s]", key, err.Error())
+		resp.SetError(errNotFound)
+		writeResponse(w, resp, http.StatusNotFound)
+		return
+	}
+
+	resp.SetSuccess()
+	writeResponse(w, resp, http.StatusNoContent)
+}
@@ -0,0 +1,99 @@
+// пакет с api
+package api
+
+import (
+	"errors"
+	"lru"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ошибки условной записи
+var (
+	errPreconditionNotMet = errors.New("precondition failed")
+	errBadPrecondition    = errors.New("unsupported precondition")
+)
+
+// формирование ETag по версии записи
+func formatETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// разбор версии записи из ETag
+func parseETag(etag string) (uint64, bool) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(etag[1:len(etag)-1], 10, 64)
+	return version, err == nil
+}
+
+// условие на текущую запись по заголовкам If-Match и If-None-Match. If-None-Match: * - записи не должно быть,
+// If-Match: * - запись должна существовать, If-Match с ETag - версия записи должна совпадать.
+// Возвращает false, если условие не задано.
+func parsePrecondition(r *http.Request) (lru.Precondition, bool, error) {
+	ifMatch := r.Header.Get("If-Match")
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	switch {
+	case ifMatch == "" && ifNoneMatch == "":
+		return lru.Precondition{}, false, nil
+	case ifNoneMatch != "" && ifNoneMatch != "*":
+		return lru.Precondition{}, true, errBadPrecondition
+	case ifMatch != "" && ifNoneMatch != "":
+		// запись не может одновременно существовать и отсутствовать
+		return lru.Precondition{}, true, errPreconditionNotMet
+	case ifNoneMatch == "*":
+		return lru.Precondition{Absent: true}, true, nil
+	case ifMatch == "*":
+		return lru.Precondition{Exists: true}, true, nil
+	}
+
+	version, ok := parseETag(ifMatch)
+	if !ok || version == 0 {
+		return lru.Precondition{}, true, errPreconditionNotMet
+	}
+	return lru.Precondition{Version: version}, true, nil
+}
+
+// кеш с условными операциями. Кеш без их поддержки допускается только для запросов без условия,
+// тогда возвращается nil.
+func (h *CacheHandler) conditionalCache(conditional bool) (lru.IConditionalCache, error) {
+	condCache, ok := h.cache.(lru.IConditionalCache)
+	if !ok && conditional {
+		return nil, errNotSupportedByCache
+	}
+	return condCache, nil
+}
+
+// приведение ошибки несоблюдения условия кеша к ошибке условного запроса
+func preconditionError(err error) error {
+	if err != nil && err.Error() == lru.ErrPrecondition {
+		return errPreconditionNotMet
+	}
+	return err
+}
+
+// запись ответа на ошибку разбора или проверки условия запроса. Возвращает false, если err не относится к условию.
+func writePreconditionError(w http.ResponseWriter, resp interface{ SetError(string) }, key string, err error) bool {
+	switch err {
+	case errBadPrecondition:
+		log.Errorf("wrong precondition for key [%s]", key)
+		resp.SetError(errWrongParams)
+		writeResponse(w, resp, http.StatusBadRequest)
+	case errPreconditionNotMet:
+		log.Infof("precondition failed for key [%s]", key)
+		resp.SetError(errPrecondition)
+		writeResponse(w, resp, http.StatusPreconditionFailed)
+	case errNotSupportedByCache:
+		log.Errorf("cache does not support requested operation for key [%s]", key)
+		resp.SetError(errNotSupported)
+		writeResponse(w, resp, http.StatusNotImplemented)
+	default:
+		return false
+	}
+	return true
+}
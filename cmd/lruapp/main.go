@@ -20,6 +20,20 @@ func main() {
 	signal.Notify(sigint, os.Interrupt, syscall.SIGINT)
 	signal.Notify(sigterm, syscall.SIGTERM)
 
+	conf, err := config.InitConf()
+	if err != nil {
+		log.Errorf("failed to init config with error [%s]", err.Error())
+		os.Exit(1)
+	}
+
+	config.SetLogLevel(conf.LogLevel)
+
+	lruCache, err := newCache(conf)
+	if err != nil {
+		log.Errorf("failed to create cache with error [%s]", err.Error())
+		os.Exit(1)
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 
@@ -30,36 +44,83 @@ func main() {
 			select {
 			case <-sigint:
 				log.Println("Received SIGINT")
+				stopCache(lruCache)
 				os.Exit(0)
 			case <-sigterm:
 				log.Println("Received SIGTERM")
+				stopCache(lruCache)
 				os.Exit(0)
 			}
 		}
 	}()
 
 	go func() {
-		processRequests()
+		processRequests(conf, lruCache)
 	}()
 
 	wg.Wait()
 }
 
-// ф-я создания кофигурации, кеша и запуска сервера
-func processRequests() {
-	conf, err := config.InitConf()
-	if err != nil {
-		log.Errorf("failed to init config with error [%s]", err.Error())
-		os.Exit(1)
+// ф-я создания кеша согласно конфигурации
+func newCache(conf config.Conf) (lru.ILRUCache, error) {
+	// проверка названия стратегии до создания шардов
+	if _, err := lru.NewPolicy[string](conf.CachePolicy, conf.CacheSize); err != nil {
+		return nil, err
 	}
 
-	config.SetLogLevel(conf.LogLevel)
+	opts := []lru.Option{
+		lru.WithDefaultTTL(conf.DefaultCacheTTL),
+		lru.WithNegativeTTL(conf.NegativeCacheTTL),
+		lru.WithMaxTTL(conf.MaxCacheTTL),
+		lru.WithTTLJitter(conf.CacheTTLJitter),
+	}
+
+	shards := lru.ShardsCount(conf.CacheSize, conf.CacheShards)
+
+	// лимит размера делится между фактически созданными шардами, остаток достаётся первым шардам.
+	// Каждый шард ограничен своей долей, поэтому запись больше доли шарда отклоняется.
+	created := 0
+	newShard := func(capacity int) *lru.LRUCache[string, interface{}] {
+		policy, _ := lru.NewPolicy[string](conf.CachePolicy, capacity)
+		shard := lru.NewWithPolicy[string, interface{}](capacity, policy, opts...)
+		if conf.CacheMaxBytes > 0 {
+			maxCost := conf.CacheMaxBytes / int64(shards)
+			if int64(created) < conf.CacheMaxBytes%int64(shards) {
+				maxCost++
+			}
+			shard.SetMaxCost(max(maxCost, 1), nil)
+		}
+		created++
+		return shard
+	}
+
+	var lruCache lru.ILRUCache
+	if shards > 1 {
+		lruCache = lru.NewShardedLRUCacheFunc(conf.CacheSize, conf.CacheShards, newShard)
+	} else {
+		lruCache = newShard(conf.CacheSize)
+	}
+
+	if janitor, ok := lruCache.(lru.IJanitor); ok && conf.JanitorInterval > 0 {
+		janitor.StartJanitor(conf.JanitorInterval)
+	}
+
+	return lruCache, nil
+}
+
+// ф-я остановки фоновых процессов кеша
+func stopCache(lruCache lru.ILRUCache) {
+	if janitor, ok := lruCache.(lru.IJanitor); ok {
+		janitor.StopJanitor()
+	}
+}
 
-	lruCache := lru.NewLRUCache(conf.CacheSize)
-	cacheHandler := api.NewCacheHandler(lruCache, conf.DefaultCacheTTL)
+// ф-я запуска сервера
+func processRequests(conf config.Conf, lruCache lru.ILRUCache) {
+	cacheHandler := api.NewCacheHandler(lruCache, conf.RefreshOrigin)
 
 	router := api.NewRouter(cacheHandler)
-	err = http.ListenAndServe(conf.ServerHostPort, router)
+	err := http.ListenAndServe(conf.ServerHostPort, router)
 	if err != nil {
 		log.Fatalf("failed to listen and serve with error [%s]", err.Error())
 		os.Exit(1)
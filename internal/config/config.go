@@ -2,7 +2,9 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"net/url"
 	"time"
 
 	"github.com/caarlos0/env/v8"
@@ -13,10 +15,18 @@ const defaultLevel log.Level = log.WarnLevel // Уровень логирова
 
 // тип с конфигурацией настроек сервиса
 type Conf struct {
-	ServerHostPort  string        `env:"SERVER_HOST_PORT" envDefault:"localhost:8080"`
-	CacheSize       int           `env:"CACHE_SIZE" envDefault:"10"`
-	DefaultCacheTTL time.Duration `env:"DEFAULT_CACHE_TTL" envDefault:"1m"`
-	LogLevel        string        `env:"LOG_LEVEL" envDefault:"WARN"`
+	ServerHostPort   string        `env:"SERVER_HOST_PORT" envDefault:"localhost:8080"`
+	CacheSize        int           `env:"CACHE_SIZE" envDefault:"10"`
+	CacheMaxBytes    int64         `env:"CACHE_MAX_BYTES" envDefault:"0"`
+	CacheShards      int           `env:"CACHE_SHARDS" envDefault:"1"`
+	CachePolicy      string        `env:"CACHE_POLICY" envDefault:"lru"`
+	DefaultCacheTTL  time.Duration `env:"DEFAULT_CACHE_TTL" envDefault:"1m"`
+	NegativeCacheTTL time.Duration `env:"NEGATIVE_CACHE_TTL" envDefault:"10s"`
+	MaxCacheTTL      time.Duration `env:"MAX_CACHE_TTL" envDefault:"0s"`
+	CacheTTLJitter   float64       `env:"CACHE_TTL_JITTER" envDefault:"0"`
+	JanitorInterval  time.Duration `env:"CACHE_JANITOR_INTERVAL" envDefault:"0s"`
+	RefreshOrigin    string        `env:"REFRESH_ORIGIN_URL" envDefault:""`
+	LogLevel         string        `env:"LOG_LEVEL" envDefault:"WARN"`
 }
 
 // инициализация конфигурации
@@ -29,16 +39,40 @@ func InitConf() (Conf, error) {
 
 	serverHostPort := flag.String("server-host-port", conf.ServerHostPort, "Server host and port")
 	cacheSize := flag.Int("cache-size", conf.CacheSize, "Cache size")
+	cacheMaxBytes := flag.Int64("cache-max-bytes", conf.CacheMaxBytes, "Cache max total size of values in bytes, split evenly between shards; entries larger than a shard's share are rejected. 0 disables the limit")
+	cacheShards := flag.Int("cache-shards", conf.CacheShards, "Cache shards count")
+	cachePolicy := flag.String("cache-policy", conf.CachePolicy, "Cache eviction policy")
 	defaultCacheTTL := flag.Duration("default-cache-ttl", conf.DefaultCacheTTL, "Default cache TTL")
+	negativeCacheTTL := flag.Duration("negative-cache-ttl", conf.NegativeCacheTTL, "Default TTL of not found entries")
+	maxCacheTTL := flag.Duration("max-cache-ttl", conf.MaxCacheTTL, "Max cache TTL, 0 disables the limit")
+	cacheTTLJitter := flag.Float64("cache-ttl-jitter", conf.CacheTTLJitter, "Max random fraction cut from entry TTL, 0 disables jitter")
+	janitorInterval := flag.Duration("cache-janitor-interval", conf.JanitorInterval, "Expired entries sweep interval, 0 disables janitor")
+	refreshOrigin := flag.String("refresh-origin-url", conf.RefreshOrigin, "Origin URL prefix for refresh-ahead loads, empty disables refresh-ahead")
 	logLevel := flag.String("log-level", conf.LogLevel, "Log level")
 
 	flag.Parse()
 
 	conf.ServerHostPort = *serverHostPort
 	conf.CacheSize = *cacheSize
+	conf.CacheMaxBytes = *cacheMaxBytes
+	conf.CacheShards = *cacheShards
+	conf.CachePolicy = *cachePolicy
 	conf.DefaultCacheTTL = *defaultCacheTTL
+	conf.NegativeCacheTTL = *negativeCacheTTL
+	conf.MaxCacheTTL = *maxCacheTTL
+	conf.CacheTTLJitter = *cacheTTLJitter
+	conf.JanitorInterval = *janitorInterval
+	conf.RefreshOrigin = *refreshOrigin
 	conf.LogLevel = *logLevel
 
+	if conf.RefreshOrigin != "" {
+		origin, err := url.Parse(conf.RefreshOrigin)
+		if err != nil || (origin.Scheme != "http" && origin.Scheme != "https") {
+			log.Errorf("wrong refresh origin url [%s]", conf.RefreshOrigin)
+			return Conf{}, errors.New("refresh origin url must be http or https")
+		}
+	}
+
 	log.Infof("config [%+v]", conf)
 
 	return conf, nil
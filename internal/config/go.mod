@@ -1,6 +1,6 @@
 module config
 
-go 1.22
+go 1.24
 
 require (
 	github.com/caarlos0/env/v8 v8.0.0
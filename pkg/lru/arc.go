@@ -0,0 +1,170 @@
+package lru
+
+import (
+	"container/list"
+)
+
+// списки ARC: T1 и T2 содержат ключи записей кеша, B1 и B2 - "призрачные" ключи недавно вытесненных записей
+const (
+	arcT1 = iota // ключи, к которым обращались один раз
+	arcT2        // ключи, к которым обращались повторно
+	arcB1        // вытесненные из T1
+	arcB2        // вытесненные из T2
+)
+
+// положение ключа в одном из списков ARC
+type arcEntry[K comparable] struct {
+	key  K
+	list int
+}
+
+// стратегия ARC (Adaptive Replacement Cache): целевой размер T1 адаптируется
+// по попаданиям в призрачные списки, что защищает часто используемые ключи от вытеснения сканированием
+type arcPolicy[K comparable] struct {
+	capacity int
+	target   int
+	fromB2   bool
+	lists    [4]*list.List
+	elements map[K]*list.Element
+}
+
+// создание стратегии ARC, capacity ограничивает размер призрачных списков
+func NewARCPolicy[K comparable](capacity int) EvictionPolicy[K] {
+	p := &arcPolicy[K]{
+		capacity: capacity,
+		elements: make(map[K]*list.Element),
+	}
+	for i := range p.lists {
+		p.lists[i] = list.New()
+	}
+	return p
+}
+
+// создание ARC-кеша со строковыми ключами, удовлетворяющего ILRUCache
+func NewARCCache(capacity int, opts ...LRUOption) *LRUCache[string, interface{}] {
+	return withLRUOptions(NewWithPolicy[string, interface{}](capacity, NewARCPolicy[string](capacity)), opts)
+}
+
+// адаптация целевого размера T1 при повторном появлении вытесненного ключа
+func (p *arcPolicy[K]) BeforeInsert(key K) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+
+	b1, b2 := p.lists[arcB1].Len(), p.lists[arcB2].Len()
+	switch element.Value.(*arcEntry[K]).list {
+	case arcB1:
+		p.target = min(p.target+max(b2/b1, 1), p.capacity)
+	case arcB2:
+		p.target = max(p.target-max(b1/b2, 1), 0)
+		p.fromB2 = true
+	}
+}
+
+func (p *arcPolicy[K]) OnInsert(key K) {
+	if element, ok := p.elements[key]; ok {
+		// ключ из призрачного списка сразу попадает в T2
+		p.move(element, arcT2)
+	} else {
+		p.elements[key] = p.lists[arcT1].PushFront(&arcEntry[K]{key: key, list: arcT1})
+	}
+	p.fromB2 = false
+	p.trim()
+}
+
+func (p *arcPolicy[K]) OnAccess(key K) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+	if where := element.Value.(*arcEntry[K]).list; where == arcT1 || where == arcT2 {
+		p.move(element, arcT2)
+	}
+}
+
+func (p *arcPolicy[K]) OnRemove(key K) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+	entry := element.Value.(*arcEntry[K])
+	if entry.list == arcT1 || entry.list == arcT2 {
+		p.lists[entry.list].Remove(element)
+		delete(p.elements, key)
+	}
+}
+
+func (p *arcPolicy[K]) Evict() (K, bool) {
+	t1 := p.lists[arcT1].Len()
+
+	var element *list.Element
+	switch {
+	case t1 > 0 && (t1 > p.target || (p.fromB2 && t1 == p.target)):
+		element = p.lists[arcT1].Back()
+	case p.lists[arcT2].Len() > 0:
+		element = p.lists[arcT2].Back()
+	case t1 > 0:
+		element = p.lists[arcT1].Back()
+	default:
+		var zero K
+		return zero, false
+	}
+
+	entry := element.Value.(*arcEntry[K])
+	if entry.list == arcT1 {
+		p.move(element, arcB1)
+	} else {
+		p.move(element, arcB2)
+	}
+	p.trim()
+	return entry.key, true
+}
+
+func (p *arcPolicy[K]) Keys() []K {
+	keys := make([]K, 0, p.lists[arcT1].Len()+p.lists[arcT2].Len())
+	for _, l := range []*list.List{p.lists[arcT2], p.lists[arcT1]} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			keys = append(keys, e.Value.(*arcEntry[K]).key)
+		}
+	}
+	return keys
+}
+
+func (p *arcPolicy[K]) Reset() {
+	for _, l := range p.lists {
+		l.Init()
+	}
+	p.elements = make(map[K]*list.Element)
+	p.target = 0
+	p.fromB2 = false
+}
+
+// перенос ключа в начало списка to
+func (p *arcPolicy[K]) move(element *list.Element, to int) {
+	entry := element.Value.(*arcEntry[K])
+	p.lists[entry.list].Remove(element)
+	entry.list = to
+	p.elements[entry.key] = p.lists[to].PushFront(entry)
+}
+
+// ограничение размера призрачных списков: |T1|+|B1| <= c, |T1|+|T2|+|B1|+|B2| <= 2c
+func (p *arcPolicy[K]) trim() {
+	for p.lists[arcB1].Len() > 0 && p.lists[arcT1].Len()+p.lists[arcB1].Len() > p.capacity {
+		p.dropGhost(arcB1)
+	}
+	total := 0
+	for _, l := range p.lists {
+		total += l.Len()
+	}
+	for p.lists[arcB2].Len() > 0 && total > 2*p.capacity {
+		p.dropGhost(arcB2)
+		total--
+	}
+}
+
+// удаление самого старого ключа из призрачного списка
+func (p *arcPolicy[K]) dropGhost(ghost int) {
+	entry := p.lists[ghost].Remove(p.lists[ghost].Back()).(*arcEntry[K])
+	delete(p.elements, entry.key)
+}
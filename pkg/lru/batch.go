@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// интерфейс кеша с пакетными операциями
+type IBatchCache interface {
+	// PutMany запись нескольких значений, ошибки располагаются на позициях соответствующих записей
+	PutMany(ctx context.Context, items []BatchItem[string, interface{}]) []error
+	// GetMany получение нескольких записей по ключам
+	GetMany(ctx context.Context, keys []string) ([]Entry[interface{}], []error)
+	// EvictMany удаление нескольких записей по ключам
+	EvictMany(ctx context.Context, keys []string) ([]interface{}, []error)
+}
+
+// запись пакетного добавления в кеш
+type BatchItem[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration
+}
+
+// добавление нескольких значений под одним захватом мьютекса. Ошибка каждой записи располагается
+// на её позиции, ошибка одной записи не отменяет остальные.
+func (lru *LRUCache[K, V]) PutMany(ctx context.Context, items []BatchItem[K, V]) []error {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	errs := make([]error, len(items))
+	for i, item := range items {
+		_, errs[i] = lru.put(item.Key, item.Value, item.TTL, putOptions{})
+	}
+	return errs
+}
+
+// получение нескольких записей под одним захватом мьютекса, записи и ошибки располагаются на позициях ключей
+func (lru *LRUCache[K, V]) GetMany(ctx context.Context, keys []K) ([]Entry[V], []error) {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	entries := make([]Entry[V], len(keys))
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		entries[i], errs[i] = lru.lookup(ctx, key, nil)
+	}
+	return entries, errs
+}
+
+// удаление нескольких записей под одним захватом мьютекса, удалённые значения и ошибки располагаются на позициях ключей
+func (lru *LRUCache[K, V]) EvictMany(ctx context.Context, keys []K) ([]V, []error) {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		pair, ok := lru.cache[key]
+		if !ok {
+			errs[i] = errors.New(ErrKeyNotFound)
+			continue
+		}
+		lru.removePair(pair, EvictionReasonManual)
+		values[i] = pair.value
+	}
+	return values, errs
+}
@@ -0,0 +1,114 @@
+package lru
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+// интерфейс кеша с целочисленными счётчиками
+type ICounterCache interface {
+	// Incr увеличение значения по ключу на delta, возвращает новое значение
+	Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// Decr уменьшение значения по ключу на delta, возвращает новое значение
+	Decr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// увеличение целого значения по ключу на delta. Отсутствующий ключ создаётся со значением delta и TTL ttl,
+// у существующего сохраняются время истечения и параметры записи. Значение хранится как int64; числа с плавающей точкой
+// без дробной части (например, полученные из JSON) принимаются, остальные значения дают ErrNotNumeric.
+// При выходе за пределы int64 возвращается ErrOverflow, значение не меняется.
+func (lru *LRUCache[K, V]) Incr(ctx context.Context, key K, delta int64, ttl time.Duration) (int64, error) {
+	return lru.IncrIf(ctx, key, delta, ttl, Precondition{})
+}
+
+// увеличение целого значения по ключу по правилам Incr при соблюдении условия cond, иначе ErrPrecondition
+func (lru *LRUCache[K, V]) IncrIf(ctx context.Context, key K, delta int64, ttl time.Duration, cond Precondition) (int64, error) {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	if err := lru.checkPrecondition(key, cond); err != nil {
+		return 0, err
+	}
+	var current int64
+	options := putOptions{}
+	pair, ok := lru.present(key)
+	if ok {
+		var numeric bool
+		current, numeric = toInt64(pair.value)
+		if !numeric {
+			return 0, errors.New(ErrNotNumeric)
+		}
+		ttl = pair.ttl
+		options = pair.keptOptions()
+		options.expiresAt = pair.expiresAt
+		options.maxExpiresAt = pair.maxExpiresAt
+	}
+
+	result := current + delta
+	if (delta > 0 && result < current) || (delta < 0 && result > current) {
+		return 0, errors.New(ErrOverflow)
+	}
+
+	value, ok := any(result).(V)
+	if !ok {
+		return 0, errors.New(ErrNotNumeric)
+	}
+	_, err := lru.put(key, value, ttl, options)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// уменьшение целого значения по ключу на delta по правилам Incr
+func (lru *LRUCache[K, V]) Decr(ctx context.Context, key K, delta int64, ttl time.Duration) (int64, error) {
+	if delta == math.MinInt64 {
+		return 0, errors.New(ErrOverflow)
+	}
+	return lru.Incr(ctx, key, -delta, ttl)
+}
+
+// приведение значения к int64 без потери точности
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return floatToInt64(float64(v))
+	case float64:
+		return floatToInt64(v)
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// приведение числа с плавающей точкой без дробной части к int64
+func floatToInt64(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
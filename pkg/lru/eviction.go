@@ -0,0 +1,75 @@
+package lru
+
+// причина удаления записи из кеша
+type EvictionReason int
+
+const (
+	EvictionReasonCapacity EvictionReason = iota // вытеснена из-за нехватки места
+	EvictionReasonExpired                        // истёк срок действия
+	EvictionReasonManual                         // удалена вызовом Evict
+	EvictionReasonCleared                        // удалена вызовом EvictAll
+)
+
+// строковое представление причины удаления
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionReasonCapacity:
+		return "capacity"
+	case EvictionReasonExpired:
+		return "expired"
+	case EvictionReasonManual:
+		return "manual"
+	case EvictionReasonCleared:
+		return "cleared"
+	}
+	return "unknown"
+}
+
+// обработчик удаления записи из кеша
+type EvictionListener[K comparable, V any] func(key K, value V, reason EvictionReason)
+
+// событие удаления, накопленное под мьютексом и ожидающее отправки обработчикам
+type evictionEvent[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// регистрация обработчика удаления записей. Обработчики вызываются вне мьютекса кеша,
+// поэтому из них можно обращаться к кешу.
+func (lru *LRUCache[K, V]) OnEvict(listener EvictionListener[K, V]) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	lru.listeners = append(lru.listeners, listener)
+}
+
+// фиксация события удаления для последующей отправки. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) recordEviction(pair *Pair[K, V], reason EvictionReason) {
+	if len(lru.listeners) == 0 {
+		return
+	}
+	lru.pending = append(lru.pending, evictionEvent[K, V]{pair.key, pair.value, reason})
+}
+
+// снятие мьютекса с последующей отправкой накопленных событий удаления обработчикам
+func (lru *LRUCache[K, V]) unlock() {
+	lru.release()()
+}
+
+// снятие мьютекса, возвращает ф-ю отправки накопленных событий удаления обработчикам.
+// Позволяет снять мьютексы нескольких кешей до вызова обработчиков.
+func (lru *LRUCache[K, V]) release() func() {
+	events := lru.pending
+	lru.pending = nil
+	listeners := lru.listeners
+	lru.mu.Unlock()
+
+	return func() {
+		for _, event := range events {
+			for _, listener := range listeners {
+				listener(event.key, event.value, event.reason)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package lru
+
+import (
+	"container/heap"
+	"time"
+)
+
+// интерфейс кеша с фоновой очисткой просроченных записей
+type IJanitor interface {
+	// StartJanitor запуск фоновой очистки с заданным интервалом
+	StartJanitor(interval time.Duration)
+	// StopJanitor остановка фоновой очистки
+	StopJanitor()
+}
+
+// индекс записей кеша, упорядоченный по моменту удаления записи (min-heap), бессрочные записи располагаются в конце
+type expiryHeap[K comparable, V any] []*Pair[K, V]
+
+func (h expiryHeap[K, V]) Len() int { return len(h) }
+
+func (h expiryHeap[K, V]) Less(i, j int) bool {
+	di, dj := h[i].deadline(), h[j].deadline()
+	if di.IsZero() || dj.IsZero() {
+		return dj.IsZero() && !di.IsZero()
+	}
+	return di.Before(dj)
+}
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	pair := x.(*Pair[K, V])
+	pair.heapIndex = len(*h)
+	*h = append(*h, pair)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	pair := old[n-1]
+	old[n-1] = nil
+	pair.heapIndex = -1
+	*h = old[:n-1]
+	return pair
+}
+
+// запуск фоновой очистки просроченных записей с заданным интервалом.
+// Повторный вызов перезапускает очистку с новым интервалом.
+func (lru *LRUCache[K, V]) StartJanitor(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	lru.StopJanitor()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	lru.mu.Lock()
+	lru.janitorStop = stop
+	lru.janitorDone = done
+	lru.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				lru.DeleteExpired()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// остановка фоновой очистки, дожидается завершения горутины
+func (lru *LRUCache[K, V]) StopJanitor() {
+	lru.mu.Lock()
+	stop, done := lru.janitorStop, lru.janitorDone
+	lru.janitorStop, lru.janitorDone = nil, nil
+	lru.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// удаление всех просроченных записей, возвращает количество удалённых
+func (lru *LRUCache[K, V]) DeleteExpired() int {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	now := lru.now()
+	deleted := 0
+	for len(lru.expiry) > 0 && lru.expiry[0].pastDeadline(now) {
+		lru.removePair(lru.expiry[0], EvictionReasonExpired)
+		deleted++
+	}
+	return deleted
+}
+
+// добавление записи в индекс истечения
+func (lru *LRUCache[K, V]) trackExpiry(pair *Pair[K, V]) {
+	heap.Push(&lru.expiry, pair)
+}
+
+// обновление позиции записи в индексе после изменения срока действия
+func (lru *LRUCache[K, V]) fixExpiry(pair *Pair[K, V]) {
+	heap.Fix(&lru.expiry, pair.heapIndex)
+}
+
+// удаление записи из индекса истечения
+func (lru *LRUCache[K, V]) untrackExpiry(pair *Pair[K, V]) {
+	if pair.heapIndex >= 0 {
+		heap.Remove(&lru.expiry, pair.heapIndex)
+	}
+}
@@ -0,0 +1,77 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// интерфейс кеша с чтением без изменения состояния: порядок вытеснения, срок действия
+// и статистика не меняются, просроченные записи не удаляются
+type IInspectCache interface {
+	// Peek получение значения по ключу без изменения его позиции
+	Peek(ctx context.Context, key string) (value interface{}, expiresAt time.Time, err error)
+	// Contains наличие актуального значения по ключу
+	Contains(ctx context.Context, key string) bool
+	// Len количество хранимых записей
+	Len() int
+	// Capacity максимальное количество записей, 0 - без ограничения
+	Capacity() int
+	// Keys ключи актуальных записей от недавно использованных к давно использованным
+	Keys(ctx context.Context) []string
+}
+
+// получение значения и времени истечения по ключу без изменения его позиции в порядке вытеснения
+func (lru *LRUCache[K, V]) Peek(ctx context.Context, key K) (V, time.Time, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	var zero V
+	pair, ok := lru.cache[key]
+	if !ok {
+		return zero, time.Time{}, errors.New(ErrKeyNotFound)
+	}
+	if pair.expired(lru.now()) {
+		return zero, time.Time{}, errors.New(ErrKeyExpired)
+	}
+	if pair.negative {
+		return zero, pair.expiry(), errors.New(ErrKeyNegative)
+	}
+	return pair.value, pair.expiry(), nil
+}
+
+// наличие актуального значения по ключу
+func (lru *LRUCache[K, V]) Contains(ctx context.Context, key K) bool {
+	_, _, err := lru.Peek(ctx, key)
+	return err == nil
+}
+
+// количество хранимых записей, включая ещё не удалённые просроченные и записи об отсутствии ключа
+func (lru *LRUCache[K, V]) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return len(lru.cache)
+}
+
+// максимальное количество записей, 0 - без ограничения
+func (lru *LRUCache[K, V]) Capacity() int {
+	return max(lru.capacity, 0)
+}
+
+// ключи актуальных записей в порядке стратегии вытеснения, для LRU - от недавно использованных
+// к давно использованным
+func (lru *LRUCache[K, V]) Keys(ctx context.Context) []K {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	now := lru.now()
+	keys := make([]K, 0, len(lru.cache))
+	for _, key := range lru.policy.Keys() {
+		pair := lru.cache[key]
+		if !pair.negative && !pair.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
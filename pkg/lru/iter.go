@@ -0,0 +1,89 @@
+package lru
+
+import (
+	"iter"
+	"time"
+)
+
+// режим обхода записей кеша
+type IterationMode int
+
+const (
+	// снимок записей делается под мьютексом целиком, обход не видит последующих изменений
+	IterateSnapshot IterationMode = iota
+	// под мьютексом запоминается только порядок ключей, каждая запись читается отдельно в момент обхода.
+	// Удалённые к этому моменту записи пропускаются, добавленные после начала обхода не попадают в него.
+	IterateLive
+)
+
+// интерфейс кеша с обходом записей
+type IIterableCache interface {
+	// All обход актуальных записей в порядке вытеснения
+	All(mode IterationMode) iter.Seq2[string, Entry[interface{}]]
+}
+
+// обход актуальных записей в порядке стратегии вытеснения, для LRU - от недавно использованных
+// к давно использованным. Обход не меняет порядок вытеснения и не удаляет просроченные записи;
+// просроченные записи и записи об отсутствии ключа пропускаются.
+func (lru *LRUCache[K, V]) All(mode IterationMode) iter.Seq2[K, Entry[V]] {
+	if mode == IterateLive {
+		return lru.live()
+	}
+	return lru.snapshot()
+}
+
+// обход снимка записей
+func (lru *LRUCache[K, V]) snapshot() iter.Seq2[K, Entry[V]] {
+	return func(yield func(K, Entry[V]) bool) {
+		lru.mu.Lock()
+		keys := make([]K, 0, len(lru.cache))
+		entries := make([]Entry[V], 0, len(lru.cache))
+		now := lru.now()
+		for rank, key := range lru.policy.Keys() {
+			if entry, ok := lru.entry(key, rank, now); ok {
+				keys = append(keys, key)
+				entries = append(entries, entry)
+			}
+		}
+		lru.mu.Unlock()
+
+		for i, key := range keys {
+			if !yield(key, entries[i]) {
+				return
+			}
+		}
+	}
+}
+
+// слабо согласованный обход без удержания мьютекса на всё время обхода
+func (lru *LRUCache[K, V]) live() iter.Seq2[K, Entry[V]] {
+	return func(yield func(K, Entry[V]) bool) {
+		lru.mu.Lock()
+		keys := lru.policy.Keys()
+		lru.mu.Unlock()
+
+		for rank, key := range keys {
+			lru.mu.Lock()
+			entry, ok := lru.entry(key, rank, lru.now())
+			lru.mu.Unlock()
+
+			if !ok {
+				continue
+			}
+			if !yield(key, entry) {
+				return
+			}
+		}
+	}
+}
+
+// представление актуальной записи для обхода. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) entry(key K, rank int, now time.Time) (Entry[V], bool) {
+	pair, ok := lru.cache[key]
+	if !ok || pair.negative || pair.expired(now) {
+		return Entry[V]{}, false
+	}
+	entry := pair.entry()
+	entry.Rank = rank
+	return entry, true
+}
@@ -0,0 +1,113 @@
+package lru
+
+import (
+	"container/list"
+)
+
+// корзина ключей с одинаковой частотой обращений
+type lfuBucket[K comparable] struct {
+	freq int
+	keys *list.List
+}
+
+// положение ключа в стратегии LFU
+type lfuEntry[K comparable] struct {
+	key    K
+	bucket *list.Element
+}
+
+// стратегия LFU со сложностью O(1): корзины частот упорядочены по возрастанию,
+// внутри корзины ключи упорядочены по давности обращения
+type lfuPolicy[K comparable] struct {
+	buckets  *list.List
+	elements map[K]*list.Element
+}
+
+// создание стратегии LFU
+func NewLFUPolicy[K comparable]() EvictionPolicy[K] {
+	return &lfuPolicy[K]{
+		buckets:  list.New(),
+		elements: make(map[K]*list.Element),
+	}
+}
+
+// создание LFU-кеша со строковыми ключами, удовлетворяющего ILRUCache
+func NewLFUCache(capacity int, opts ...LRUOption) *LRUCache[string, interface{}] {
+	return withLRUOptions(NewWithPolicy[string, interface{}](capacity, NewLFUPolicy[string]()), opts)
+}
+
+func (p *lfuPolicy[K]) OnInsert(key K) {
+	front := p.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket[K]).freq != 1 {
+		front = p.buckets.PushFront(&lfuBucket[K]{freq: 1, keys: list.New()})
+	}
+	p.elements[key] = front.Value.(*lfuBucket[K]).keys.PushFront(&lfuEntry[K]{key: key, bucket: front})
+}
+
+func (p *lfuPolicy[K]) OnAccess(key K) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+	entry := element.Value.(*lfuEntry[K])
+	current := entry.bucket
+	bucket := current.Value.(*lfuBucket[K])
+
+	next := current.Next()
+	if next == nil || next.Value.(*lfuBucket[K]).freq != bucket.freq+1 {
+		next = p.buckets.InsertAfter(&lfuBucket[K]{freq: bucket.freq + 1, keys: list.New()}, current)
+	}
+
+	bucket.keys.Remove(element)
+	if bucket.keys.Len() == 0 {
+		p.buckets.Remove(current)
+	}
+	entry.bucket = next
+	p.elements[key] = next.Value.(*lfuBucket[K]).keys.PushFront(entry)
+}
+
+func (p *lfuPolicy[K]) OnRemove(key K) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+	p.remove(element)
+}
+
+func (p *lfuPolicy[K]) Evict() (K, bool) {
+	front := p.buckets.Front()
+	if front == nil {
+		var zero K
+		return zero, false
+	}
+	element := front.Value.(*lfuBucket[K]).keys.Back()
+	key := element.Value.(*lfuEntry[K]).key
+	p.remove(element)
+	return key, true
+}
+
+func (p *lfuPolicy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.elements))
+	for b := p.buckets.Back(); b != nil; b = b.Prev() {
+		for e := b.Value.(*lfuBucket[K]).keys.Front(); e != nil; e = e.Next() {
+			keys = append(keys, e.Value.(*lfuEntry[K]).key)
+		}
+	}
+	return keys
+}
+
+func (p *lfuPolicy[K]) Reset() {
+	p.buckets.Init()
+	p.elements = make(map[K]*list.Element)
+}
+
+// удаление ключа из корзины, пустая корзина удаляется
+func (p *lfuPolicy[K]) remove(element *list.Element) {
+	entry := element.Value.(*lfuEntry[K])
+	bucket := entry.bucket.Value.(*lfuBucket[K])
+	bucket.keys.Remove(element)
+	if bucket.keys.Len() == 0 {
+		p.buckets.Remove(entry.bucket)
+	}
+	delete(p.elements, entry.key)
+}
@@ -0,0 +1,111 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// ф-я загрузки значения при промахе кеша, возвращает значение и его TTL
+type LoaderFunc[V any] func(ctx context.Context) (value V, ttl time.Duration, err error)
+
+// выполняющаяся загрузка значения, результат которой ожидают все обратившиеся за ключом
+type loadCall[V any] struct {
+	done    chan struct{}
+	version uint64 // версия записи на момент запуска загрузки, 0 - записи не было
+	entry   Entry[V]
+	err     error
+}
+
+// получение записи по ключу, при промахе значение загружается ф-ей loader и записывается в кеш.
+// Одновременные промахи по одному ключу ожидают единственный вызов loader, ошибка загрузки
+// возвращается всем ожидающим. Отмена контекста прекращает ожидание только для вызвавшего,
+// загрузка при этом продолжается для остальных. Загруженное значение записывается в кеш, только если
+// запись за время загрузки не изменилась и не была удалена, ожидающие получают его в любом случае.
+// В период устаревания записи сразу возвращается
+// устаревшее значение, а загрузка выполняется в фоне; при её ошибке устаревшее значение
+// продолжает отдаваться до конца периода. Если loader возвращает ошибку ErrKeyNotFound с положительным TTL,
+// в кеш записывается запись об отсутствии ключа, и до её истечения возвращается ErrKeyNegative.
+func (lru *LRUCache[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[V]) (Entry[V], error) {
+	lru.mu.Lock()
+	entry, err := lru.lookup(ctx, key, loader)
+	if err == nil || entry.Negative {
+		lru.unlock()
+		return entry, err
+	}
+
+	call := lru.startLoad(context.WithoutCancel(ctx), key, loader)
+	lru.unlock()
+
+	select {
+	case <-call.done:
+		return call.entry, call.err
+	case <-ctx.Done():
+		return Entry[V]{}, ctx.Err()
+	}
+}
+
+// запуск загрузки значения по ключу, если она ещё не выполняется. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) startLoad(ctx context.Context, key K, loader LoaderFunc[V]) *loadCall[V] {
+	if call, ok := lru.loads[key]; ok {
+		return call
+	}
+
+	call := &loadCall[V]{done: make(chan struct{}), version: lru.cachedVersion(key)}
+	if lru.loads == nil {
+		lru.loads = make(map[K]*loadCall[V])
+	}
+	lru.loads[key] = call
+	go lru.load(ctx, key, loader, call)
+	return call
+}
+
+// выполнение загрузки значения и запись его в кеш
+func (lru *LRUCache[K, V]) load(ctx context.Context, key K, loader LoaderFunc[V], call *loadCall[V]) {
+	defer func() {
+		lru.mu.Lock()
+		delete(lru.loads, key)
+		lru.mu.Unlock()
+		close(call.done)
+	}()
+
+	value, ttl, err := loader(ctx)
+
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	// запись, изменённая или удалённая во время загрузки, новее загруженного значения
+	unchanged := lru.cachedVersion(key) == call.version
+	if err != nil {
+		if unchanged && err.Error() == ErrKeyNotFound && ttl > 0 {
+			lru.put(key, value, ttl, putOptions{negative: true})
+		}
+		call.err = err
+		return
+	}
+
+	if !unchanged {
+		call.entry = Entry[V]{Value: value}
+		if ttl = lru.resolveTTL(ttl); ttl != NoExpiration {
+			call.entry.ExpiresAt = lru.now().Add(ttl)
+		}
+		return
+	}
+	call.entry, call.err = lru.put(key, value, ttl, lru.reloadOptions(key))
+}
+
+// версия записи по ключу с учётом просроченных записей и записей об отсутствии ключа, 0 - записи нет.
+// Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) cachedVersion(key K) uint64 {
+	if pair, ok := lru.cache[key]; ok {
+		return pair.version
+	}
+	return 0
+}
+
+// параметры записи перезагружаемого значения: период устаревания, теги и режим истечения сохраняются. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) reloadOptions(key K) putOptions {
+	if pair, ok := lru.cache[key]; ok {
+		return pair.keptOptions()
+	}
+	return putOptions{}
+}
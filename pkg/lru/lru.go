@@ -2,7 +2,6 @@
 package lru
 
 import (
-	"container/list"
 	"context"
 	"errors"
 	"sync"
@@ -11,129 +10,379 @@ import (
 
 // errors
 const (
-	ErrKeyNotFound = "key not found"
-	ErrKeyExpired  = "key expired"
+	ErrKeyNotFound     = "key not found"
+	ErrKeyExpired      = "key expired"
+	ErrEntryTooLarge   = "entry too large"
+	ErrKeyNegative     = "key known absent"
+	ErrKeyExists       = "key already exists"
+	ErrVersionMismatch = "version mismatch"
+	ErrPrecondition    = "precondition failed"
+	ErrNotNumeric      = "value is not an integer"
+	ErrOverflow        = "integer overflow"
 )
 
-// интерфейс ILRUCache
-type ILRUCache interface {
+// обобщённый интерфейс кеша с ключами типа K и значениями типа V
+type ICache[K comparable, V any] interface {
 	// Put запись данных в кэш
-	Put(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Put(ctx context.Context, key K, value V, ttl time.Duration) error
 	// Get получение данных из кэша по ключу
-	Get(ctx context.Context, key string) (value interface{}, expiresAt time.Time, err error)
+	Get(ctx context.Context, key K) (value V, expiresAt time.Time, err error)
 	// GetAll получение всего наполнения кэша в виде двух слайсов: слайса ключей и слайса значений. Пары ключ-значения из кэша располагаются на соответствующих позициях в слайсах.
-	GetAll(ctx context.Context) (keys []string, values []interface{}, err error)
+	GetAll(ctx context.Context) (keys []K, values []V, err error)
 	// Evict ручное удаление данных по ключу
-	Evict(ctx context.Context, key string) (value interface{}, err error)
+	Evict(ctx context.Context, key K) (value V, err error)
 	// EvictAll ручная инвалидация всего кэша
 	EvictAll(ctx context.Context) error
 }
 
+// интерфейс ILRUCache со строковыми ключами и произвольными значениями
+type ILRUCache = ICache[string, interface{}]
+
 // ключ-значение в кеше со временем истечения срока действия
-type Pair struct {
-	key       string
-	value     interface{}
+type Pair[K comparable, V any] struct {
+	key       K
+	value     V
 	expiresAt time.Time
+	ttl       time.Duration
+	grace     time.Duration
+	negative  bool
+	version   uint64
+	tags      []string
+	cost      int64
+
+	sliding      bool
+	maxLifetime  time.Duration
+	maxExpiresAt time.Time // предельное время истечения для скользящего истечения, нулевое - без предела
+
+	tti        time.Duration // допустимое время простоя без чтений, 0 - без ограничения
+	lastAccess time.Time
+
+	heapIndex int
+}
+
+// момент истечения срока действия записи: истечение TTL или времени простоя, смотря что наступит раньше.
+// Нулевое время - бессрочная запись.
+func (pair *Pair[K, V]) expiry() time.Time {
+	if pair.tti > 0 {
+		if idle := pair.lastAccess.Add(pair.tti); pair.expiresAt.IsZero() || idle.Before(pair.expiresAt) {
+			return idle
+		}
+	}
+	return pair.expiresAt
+}
+
+// истёк ли срок действия записи к моменту now
+func (pair *Pair[K, V]) expired(now time.Time) bool {
+	expiry := pair.expiry()
+	return !expiry.IsZero() && now.After(expiry)
+}
+
+// момент, после которого запись удаляется из кеша: истечение срока действия с учётом периода устаревания.
+// Нулевое время - бессрочная запись.
+func (pair *Pair[K, V]) deadline() time.Time {
+	expiry := pair.expiry()
+	if expiry.IsZero() {
+		return expiry
+	}
+	return expiry.Add(pair.grace)
+}
+
+// пора ли удалить запись из кеша к моменту now
+func (pair *Pair[K, V]) pastDeadline(now time.Time) bool {
+	deadline := pair.deadline()
+	return !deadline.IsZero() && now.After(deadline)
+}
+
+// представление записи для чтения
+func (pair *Pair[K, V]) entry() Entry[V] {
+	return Entry[V]{Value: pair.value, ExpiresAt: pair.expiry(), Negative: pair.negative, Version: pair.version}
+}
+
+// представление записи кеша для чтения
+type Entry[V any] struct {
+	Value     V
+	ExpiresAt time.Time
+	Stale     bool   // срок действия истёк, значение отдаётся на время загрузки нового
+	Negative  bool   // запись об отсутствии ключа, значения нет
+	Version   uint64 // версия записи, растёт при каждой записи значения в кеш
+	Rank      int    // позиция в порядке вытеснения, 0 - недавно использованная запись; заполняется при обходе кеша
 }
 
 // структура LRU-кеша
-type LRUCache struct {
+type LRUCache[K comparable, V any] struct {
 	capacity int
-	cache    map[string]*list.Element
-	list     *list.List
+	maxCost  int64
+	cost     int64
+	weigher  Weigher[K, V]
+	cache    map[K]*Pair[K, V]
+	policy   EvictionPolicy[K]
+	expiry   expiryHeap[K, V]
+	config   cacheConfig
+	version  uint64
+	tags     map[string]map[K]struct{}
+	index    *radixTree
 	mu       sync.Mutex
+
+	listeners []EvictionListener[K, V]
+	pending   []evictionEvent[K, V]
+	stats     statsCounters
+	loads     map[K]*loadCall[V]
+
+	refreshRules []namedRefreshRule[K, V]
+
+	janitorStop chan struct{}
+	janitorDone chan struct{}
 }
 
-// создание нового LRU-кеша
-func NewLRUCache(capacity int) *LRUCache {
-	return &LRUCache{
+// создание нового LRU-кеша с ключами типа K и значениями типа V, capacity <= 0 снимает ограничение на количество записей
+func New[K comparable, V any](capacity int, opts ...Option) *LRUCache[K, V] {
+	return NewWithPolicy[K, V](capacity, NewLRUPolicy[K](), opts...)
+}
+
+// создание нового кеша с заданной стратегией вытеснения
+func NewWithPolicy[K comparable, V any](capacity int, policy EvictionPolicy[K], opts ...Option) *LRUCache[K, V] {
+	lru := &LRUCache[K, V]{
 		capacity: capacity,
-		cache:    make(map[string]*list.Element),
-		list:     list.New(),
+		cache:    make(map[K]*Pair[K, V]),
+		policy:   policy,
 	}
+	lru.configure(opts)
+	return lru
+}
+
+// создание нового LRU-кеша со строковыми ключами, удовлетворяющего ILRUCache
+func NewLRUCache(capacity int, opts ...LRUOption) *LRUCache[string, interface{}] {
+	return withLRUOptions(New[string, interface{}](capacity), opts)
 }
 
-// добавление значения в кеш по ключу
-func (lru *LRUCache) Put(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+// добавление значения в кеш по ключу. С ttl = 0 применяется TTL по умолчанию из настроек кеша,
+// без него, как и с ttl NoExpiration, запись бессрочная.
+func (lru *LRUCache[K, V]) Put(ctx context.Context, key K, value V, ttl time.Duration) error {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
-
-	expiresAt := time.Now().Add(ttl)
-	if element, ok := lru.cache[key]; ok {
-		lru.list.MoveToFront(element)
-		element.Value = Pair{key, value, expiresAt}
-		return nil
-	}
-	if lru.list.Len() == lru.capacity {
-		back := lru.list.Back()
-		if back != nil {
-			lru.list.Remove(back)
-			delete(lru.cache, back.Value.(Pair).key)
-		}
+	defer lru.unlock()
+
+	_, err := lru.put(key, value, ttl, putOptions{})
+	return err
+}
+
+// добавление значения с вытеснением записей при нехватке места, возвращает записанную запись с новой версией.
+// Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) put(key K, value V, ttl time.Duration, options putOptions) (Entry[V], error) {
+	cost := lru.weigh(key, value)
+	if lru.maxCost > 0 && cost > lru.maxCost {
+		return Entry[V]{}, errors.New(ErrEntryTooLarge)
 	}
-	pair := Pair{key, value, expiresAt}
-	element := lru.list.PushFront(pair)
-	lru.cache[key] = element
-	return nil
+
+	if options.negative && ttl == 0 && lru.config.negativeTTL > 0 {
+		ttl = lru.config.negativeTTL
+	}
+	if options.expiresAt.IsZero() {
+		ttl = lru.resolveTTL(ttl)
+	}
+	now := lru.now()
+	var expiresAt time.Time
+	if ttl != NoExpiration {
+		expiresAt = now.Add(ttl)
+	}
+	var maxExpiresAt time.Time
+	if options.sliding && options.maxLifetime > 0 {
+		maxExpiresAt = now.Add(options.maxLifetime)
+	}
+	if !options.expiresAt.IsZero() {
+		expiresAt, maxExpiresAt = options.expiresAt, options.maxExpiresAt
+	}
+	if !maxExpiresAt.IsZero() && (expiresAt.IsZero() || expiresAt.After(maxExpiresAt)) {
+		expiresAt = maxExpiresAt
+	}
+
+	lru.version++
+	if pair, ok := lru.cache[key]; ok {
+		lru.policy.OnAccess(key)
+		lru.cost += cost - pair.cost
+		pair.value = value
+		pair.expiresAt = expiresAt
+		pair.ttl = ttl
+		pair.grace = options.grace
+		pair.negative = options.negative
+		pair.version = lru.version
+		lru.untag(pair)
+		pair.tags = options.tags
+		lru.tag(pair)
+		pair.sliding = options.sliding
+		pair.maxLifetime = options.maxLifetime
+		pair.maxExpiresAt = maxExpiresAt
+		pair.tti = options.tti
+		pair.lastAccess = now
+		pair.cost = cost
+		lru.fixExpiry(pair)
+		lru.evictOverflow(0, 0)
+		return pair.entry(), nil
+	}
+
+	if observer, ok := lru.policy.(InsertObserver[K]); ok {
+		observer.BeforeInsert(key)
+	}
+	lru.evictOverflow(1, cost)
+	pair := &Pair[K, V]{key: key, value: value, expiresAt: expiresAt, ttl: ttl, grace: options.grace, negative: options.negative, version: lru.version, tags: options.tags, cost: cost,
+		sliding: options.sliding, maxLifetime: options.maxLifetime, maxExpiresAt: maxExpiresAt, tti: options.tti, lastAccess: now}
+	lru.cache[key] = pair
+	lru.tag(pair)
+	lru.indexKey(key)
+	lru.cost += cost
+	lru.policy.OnInsert(key)
+	lru.trackExpiry(pair)
+	return pair.entry(), nil
 }
 
 // получение значения по ключу из кеша
-func (lru *LRUCache) Get(ctx context.Context, key string) (interface{}, time.Time, error) {
+func (lru *LRUCache[K, V]) Get(ctx context.Context, key K) (V, time.Time, error) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
+
+	entry, err := lru.lookup(ctx, key, nil)
+	return entry.Value, entry.ExpiresAt, err
+}
+
+// поиск записи по ключу. Вызывается под мьютексом.
+// Для актуальной записи об отсутствии ключа возвращается ErrKeyNegative. Для актуальной записи отсчёт простоя начинается заново,
+// запись со скользящим истечением продлевается, запускается упреждающее обновление, если оно настроено. Запись с истёкшим сроком
+// в течение периода устаревания отдаётся как устаревшая, если задан loader, и загрузка нового значения
+// запускается в фоне; без loader возвращается ErrKeyExpired, но запись сохраняется.
+func (lru *LRUCache[K, V]) lookup(ctx context.Context, key K, loader LoaderFunc[V]) (Entry[V], error) {
+	pair, ok := lru.cache[key]
+	if !ok {
+		lru.recordMiss(key)
+		return Entry[V]{}, errors.New(ErrKeyNotFound)
+	}
 
-	if element, ok := lru.cache[key]; ok {
-		if time.Now().After(element.Value.(Pair).expiresAt) {
-			lru.list.Remove(element)
-			delete(lru.cache, key)
-			return nil, time.Time{}, errors.New(ErrKeyExpired)
+	now := lru.now()
+	if !pair.expired(now) {
+		lru.policy.OnAccess(key)
+		lru.stats.hits.Add(1)
+		if !pair.negative {
+			lru.slide(pair, now)
+			lru.markAccess(pair, now)
+		}
+		lru.maybeRefresh(ctx, pair)
+		if pair.negative {
+			return Entry[V]{ExpiresAt: pair.expiry(), Negative: true, Version: pair.version}, errors.New(ErrKeyNegative)
 		}
-		lru.list.MoveToFront(element)
-		return element.Value.(Pair).value, element.Value.(Pair).expiresAt, nil
+		return pair.entry(), nil
 	}
-	return nil, time.Time{}, errors.New(ErrKeyNotFound)
+
+	if pair.pastDeadline(now) {
+		lru.removePair(pair, EvictionReasonExpired)
+	} else if loader != nil {
+		lru.policy.OnAccess(key)
+		lru.stats.hits.Add(1)
+		lru.startLoad(context.WithoutCancel(ctx), key, loader)
+		entry := pair.entry()
+		entry.Stale = true
+		return entry, nil
+	}
+	lru.recordMiss(key)
+	lru.stats.expired.Add(1)
+	return Entry[V]{}, errors.New(ErrKeyExpired)
 }
 
-// получение всего наполнения кэша в виде двух слайсов: слайса ключей и слайса значений.
-func (lru *LRUCache) GetAll(ctx context.Context) ([]string, []interface{}, error) {
+// учёт промаха чтения в статистике и стратегии вытеснения. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) recordMiss(key K) {
+	lru.stats.misses.Add(1)
+	if observer, ok := lru.policy.(MissObserver[K]); ok {
+		observer.OnMiss(key)
+	}
+}
+
+// получение записи по ключу. Если ключ подходит под правило упреждающего обновления, его загрузчик
+// используется для отдачи устаревшего значения в период устаревания.
+func (lru *LRUCache[K, V]) GetEntry(ctx context.Context, key K) (Entry[V], error) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
-
-	keys := make([]string, 0, lru.list.Len())
-	values := make([]interface{}, 0, lru.list.Len())
-
-	for e := lru.list.Front(); e != nil; e = e.Next() {
-		if time.Now().Before(e.Value.(Pair).expiresAt) {
-			keys = append(keys, e.Value.(Pair).key)
-			values = append(values, e.Value.(Pair).value)
-		} else {
-			lru.list.Remove(e)
-			delete(lru.cache, e.Value.(Pair).key)
-		}
+	defer lru.unlock()
+
+	var loader LoaderFunc[V]
+	if rule, ok := lru.refreshRule(key); ok {
+		loader = rule.keyLoader(key)
+	}
+	return lru.lookup(ctx, key, loader)
+}
+
+// получение всего наполнения кэша в виде двух слайсов: слайса ключей и слайса значений.
+// Порядок соответствует стратегии вытеснения, для LRU - от недавно использованных к давно использованным.
+// Записи с истёкшим сроком удаляются из кеша.
+func (lru *LRUCache[K, V]) GetAll(ctx context.Context) ([]K, []V, error) {
+	lru.DeleteExpired()
+
+	keys := make([]K, 0)
+	values := make([]V, 0)
+
+	for key, entry := range lru.All(IterateSnapshot) {
+		keys = append(keys, key)
+		values = append(values, entry.Value)
 	}
 
 	return keys, values, nil
 }
 
 // удаление элемента по ключу
-func (lru *LRUCache) Evict(ctx context.Context, key string) (interface{}, error) {
+func (lru *LRUCache[K, V]) Evict(ctx context.Context, key K) (V, error) {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
 
-	if element, ok := lru.cache[key]; ok {
-		lru.list.Remove(element)
-		delete(lru.cache, key)
-		return element.Value.(Pair).value, nil
+	if pair, ok := lru.cache[key]; ok {
+		lru.removePair(pair, EvictionReasonManual)
+		return pair.value, nil
 	}
-	return nil, errors.New(ErrKeyNotFound)
+	var zero V
+	return zero, errors.New(ErrKeyNotFound)
 }
 
 // очищение всего кеша
-func (lru *LRUCache) EvictAll(ctx context.Context) error {
+func (lru *LRUCache[K, V]) EvictAll(ctx context.Context) error {
 	lru.mu.Lock()
-	defer lru.mu.Unlock()
+	defer lru.unlock()
 
-	lru.list.Init()
-	lru.cache = make(map[string]*list.Element)
+	if len(lru.listeners) > 0 {
+		for _, pair := range lru.cache {
+			lru.recordEviction(pair, EvictionReasonCleared)
+		}
+	}
+	lru.policy.Reset()
+	lru.cache = make(map[K]*Pair[K, V])
+	lru.tags = nil
+	lru.index = nil
+	lru.expiry = nil
+	lru.cost = 0
 	return nil
 }
+
+// вытеснение записей, выбранных стратегией, пока с учётом добавляемых entries записей общим весом cost
+// превышен лимит кеша. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) evictOverflow(entries int, cost int64) {
+	for lru.overflows(entries, cost) {
+		victim, ok := lru.policy.Evict()
+		if !ok {
+			return
+		}
+		lru.removePair(lru.cache[victim], EvictionReasonCapacity)
+	}
+}
+
+// удаление записи из словаря, стратегии и индекса истечения с фиксацией причины. Вызывается под мьютексом.
+// При вытеснении по вместимости ключ уже удалён из стратегии её методом Evict.
+func (lru *LRUCache[K, V]) removePair(pair *Pair[K, V], reason EvictionReason) {
+	if reason != EvictionReasonCapacity {
+		lru.policy.OnRemove(pair.key)
+	}
+	switch reason {
+	case EvictionReasonCapacity:
+		lru.stats.capacityEvictions.Add(1)
+	case EvictionReasonManual:
+		lru.stats.manualEvictions.Add(1)
+	}
+	lru.recordEviction(pair, reason)
+	delete(lru.cache, pair.key)
+	lru.untag(pair)
+	lru.unindexKey(pair.key)
+	lru.cost -= pair.cost
+	lru.untrackExpiry(pair)
+}
@@ -0,0 +1,23 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// интерфейс кеша с записями об отсутствии ключа
+type INegativeCache interface {
+	// PutNegative запись в кэш факта отсутствия ключа
+	PutNegative(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// запись в кеш факта отсутствия ключа на время ttl, при ttl = 0 - на TTL записей об отсутствии ключа
+// из настроек кеша. До истечения ttl чтение ключа возвращает ErrKeyNegative, а GetOrLoad не вызывает загрузчик.
+func (lru *LRUCache[K, V]) PutNegative(ctx context.Context, key K, ttl time.Duration) error {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	var zero V
+	_, err := lru.put(key, zero, ttl, putOptions{negative: true})
+	return err
+}
@@ -0,0 +1,58 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// интерфейс кеша, поддерживающего дополнительные параметры записи и чтение с признаком устаревания
+type IEntryCache interface {
+	// PutWithOptions запись данных в кэш с дополнительными параметрами
+	PutWithOptions(ctx context.Context, key string, value interface{}, ttl time.Duration, opts ...PutOption) error
+	// GetEntry получение записи по ключу с учётом правил упреждающего обновления
+	GetEntry(ctx context.Context, key string) (Entry[interface{}], error)
+}
+
+// параметры записи значения в кеш
+type putOptions struct {
+	grace       time.Duration
+	negative    bool
+	tags        []string
+	sliding     bool
+	maxLifetime time.Duration
+	tti         time.Duration
+
+	// сохраняемые времена истечения вместо отсчёта от текущего момента
+	expiresAt    time.Time
+	maxExpiresAt time.Time
+}
+
+// параметры существующей записи, сохраняемые при перезаписи её значения
+func (pair *Pair[K, V]) keptOptions() putOptions {
+	return putOptions{grace: pair.grace, tags: pair.tags, sliding: pair.sliding, maxLifetime: pair.maxLifetime, tti: pair.tti}
+}
+
+// необязательный параметр записи значения в кеш
+type PutOption func(*putOptions)
+
+// период после истечения TTL, в течение которого запись хранится и отдаётся чтениями с загрузчиком
+// как устаревшая, пока загружается новое значение
+func WithGracePeriod(grace time.Duration) PutOption {
+	return func(o *putOptions) {
+		o.grace = grace
+	}
+}
+
+// добавление значения в кеш по ключу с дополнительными параметрами
+func (lru *LRUCache[K, V]) PutWithOptions(ctx context.Context, key K, value V, ttl time.Duration, opts ...PutOption) error {
+	options := putOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	_, err := lru.put(key, value, ttl, options)
+	return err
+}
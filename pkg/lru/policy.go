@@ -0,0 +1,114 @@
+package lru
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// названия поддерживаемых стратегий вытеснения
+const (
+	PolicyLRU     = "lru"
+	PolicyLFU     = "lfu"
+	PolicyARC     = "arc"
+	PolicyTinyLFU = "tinylfu"
+)
+
+// стратегия вытеснения. Кеш уведомляет её о вставке, обращении и удалении ключей,
+// а при нехватке места запрашивает ключ для вытеснения. Вызывается под мьютексом кеша.
+type EvictionPolicy[K comparable] interface {
+	// OnInsert добавление нового ключа в кеш
+	OnInsert(key K)
+	// OnAccess обращение к ключу: чтение или перезапись значения
+	OnAccess(key K)
+	// OnRemove удаление ключа из кеша не по решению стратегии (истечение срока, ручное удаление)
+	OnRemove(key K)
+	// Evict выбор ключа для вытеснения, ключ удаляется из стратегии
+	Evict() (key K, ok bool)
+	// Keys ключи в порядке от наиболее ценного к ближайшему кандидату на вытеснение
+	Keys() []K
+	// Reset сброс состояния стратегии
+	Reset()
+}
+
+// необязательное расширение стратегии вытеснения: уведомление о новом ключе
+// до того, как кеш начнёт освобождать под него место
+type InsertObserver[K comparable] interface {
+	// BeforeInsert вызывается перед вытеснением записей ради ключа key
+	BeforeInsert(key K)
+}
+
+// необязательное расширение стратегии вытеснения: уведомление о чтении ключа, которого нет в кеше
+type MissObserver[K comparable] interface {
+	// OnMiss вызывается при промахе чтения по ключу key
+	OnMiss(key K)
+}
+
+// создание стратегии вытеснения по названию
+func NewPolicy[K comparable](name string, capacity int) (EvictionPolicy[K], error) {
+	switch name {
+	case PolicyLRU:
+		return NewLRUPolicy[K](), nil
+	case PolicyLFU:
+		return NewLFUPolicy[K](), nil
+	case PolicyARC:
+		return NewARCPolicy[K](capacity), nil
+	case PolicyTinyLFU:
+		return NewTinyLFUPolicy[K](capacity), nil
+	}
+	return nil, fmt.Errorf("unknown eviction policy [%s]", name)
+}
+
+// стратегия LRU: вытесняется ключ, к которому дольше всего не обращались
+type lruPolicy[K comparable] struct {
+	list     *list.List
+	elements map[K]*list.Element
+}
+
+// создание стратегии LRU
+func NewLRUPolicy[K comparable]() EvictionPolicy[K] {
+	return &lruPolicy[K]{
+		list:     list.New(),
+		elements: make(map[K]*list.Element),
+	}
+}
+
+func (p *lruPolicy[K]) OnInsert(key K) {
+	p.elements[key] = p.list.PushFront(key)
+}
+
+func (p *lruPolicy[K]) OnAccess(key K) {
+	if element, ok := p.elements[key]; ok {
+		p.list.MoveToFront(element)
+	}
+}
+
+func (p *lruPolicy[K]) OnRemove(key K) {
+	if element, ok := p.elements[key]; ok {
+		p.list.Remove(element)
+		delete(p.elements, key)
+	}
+}
+
+func (p *lruPolicy[K]) Evict() (K, bool) {
+	back := p.list.Back()
+	if back == nil {
+		var zero K
+		return zero, false
+	}
+	key := p.list.Remove(back).(K)
+	delete(p.elements, key)
+	return key, true
+}
+
+func (p *lruPolicy[K]) Keys() []K {
+	keys := make([]K, 0, p.list.Len())
+	for e := p.list.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(K))
+	}
+	return keys
+}
+
+func (p *lruPolicy[K]) Reset() {
+	p.list.Init()
+	p.elements = make(map[K]*list.Element)
+}
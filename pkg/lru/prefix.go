@@ -0,0 +1,71 @@
+package lru
+
+import (
+	"context"
+)
+
+// интерфейс кеша с выборкой и удалением записей по префиксу ключа
+type IPrefixCache interface {
+	// KeysWithPrefix ключи актуальных записей с префиксом в лексикографическом порядке
+	KeysWithPrefix(ctx context.Context, prefix string) []string
+	// EvictPrefix удаление всех записей с префиксом, возвращает количество удалённых записей
+	EvictPrefix(ctx context.Context, prefix string) int
+}
+
+// добавление ключа в индекс префиксов, индекс ведётся только для строковых ключей. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) indexKey(key K) {
+	if s, ok := any(key).(string); ok {
+		if lru.index == nil {
+			lru.index = &radixTree{}
+		}
+		lru.index.insert(s)
+	}
+}
+
+// удаление ключа из индекса префиксов. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) unindexKey(key K) {
+	if s, ok := any(key).(string); ok && lru.index != nil {
+		lru.index.delete(s)
+	}
+}
+
+// ключи хранимых записей с префиксом в лексикографическом порядке. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) prefixKeys(prefix string) []K {
+	keys := make([]K, 0)
+	if lru.index == nil {
+		return keys
+	}
+	lru.index.walkPrefix(prefix, func(s string) bool {
+		keys = append(keys, any(s).(K))
+		return true
+	})
+	return keys
+}
+
+// ключи актуальных записей с префиксом в лексикографическом порядке, порядок вытеснения не меняется.
+// Для кеша с нестроковыми ключами список пуст.
+func (lru *LRUCache[K, V]) KeysWithPrefix(ctx context.Context, prefix string) []K {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	keys := lru.prefixKeys(prefix)
+	live := keys[:0]
+	for _, key := range keys {
+		if _, ok := lru.present(key); ok {
+			live = append(live, key)
+		}
+	}
+	return live
+}
+
+// удаление всех записей с префиксом под одним захватом мьютекса, возвращает количество удалённых записей
+func (lru *LRUCache[K, V]) EvictPrefix(ctx context.Context, prefix string) int {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	keys := lru.prefixKeys(prefix)
+	for _, key := range keys {
+		lru.removePair(lru.cache[key], EvictionReasonManual)
+	}
+	return len(keys)
+}
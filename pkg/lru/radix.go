@@ -0,0 +1,138 @@
+package lru
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// узел radix-дерева: метка - часть ключа от родителя, дочерние узлы упорядочены по первому байту метки
+type radixNode struct {
+	label    string
+	leaf     bool
+	children []*radixNode
+}
+
+// упорядоченный индекс строковых ключей в виде radix-дерева
+type radixTree struct {
+	root radixNode
+}
+
+// поиск дочернего узла по первому байту метки, возвращает позицию узла или позицию для его вставки
+func (n *radixNode) child(b byte) (int, *radixNode) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label[0] >= b
+	})
+	if i < len(n.children) && n.children[i].label[0] == b {
+		return i, n.children[i]
+	}
+	return i, nil
+}
+
+// слияние узла с единственным дочерним
+func (n *radixNode) mergeChild() {
+	child := n.children[0]
+	n.label += child.label
+	n.leaf = child.leaf
+	n.children = child.children
+}
+
+// добавление ключа в индекс
+func (t *radixTree) insert(key string) {
+	n := &t.root
+	for key != "" {
+		i, child := n.child(key[0])
+		if child == nil {
+			n.children = slices.Insert(n.children, i, &radixNode{label: key, leaf: true})
+			return
+		}
+
+		common := commonPrefixLen(child.label, key)
+		if common < len(child.label) {
+			split := &radixNode{label: child.label[:common], children: []*radixNode{child}}
+			child.label = child.label[common:]
+			n.children[i] = split
+			child = split
+		}
+		n = child
+		key = key[common:]
+	}
+	n.leaf = true
+}
+
+// удаление ключа из индекса с объединением узлов, оставшихся с одним потомком
+func (t *radixTree) delete(key string) {
+	n := &t.root
+	var parent *radixNode
+	index := 0
+	for key != "" {
+		i, child := n.child(key[0])
+		if child == nil || !strings.HasPrefix(key, child.label) {
+			return
+		}
+		parent, index, n = n, i, child
+		key = key[len(child.label):]
+	}
+	if !n.leaf {
+		return
+	}
+
+	n.leaf = false
+	if parent == nil {
+		return
+	}
+	switch len(n.children) {
+	case 0:
+		parent.children = slices.Delete(parent.children, index, index+1)
+		if parent != &t.root && !parent.leaf && len(parent.children) == 1 {
+			parent.mergeChild()
+		}
+	case 1:
+		n.mergeChild()
+	}
+}
+
+// обход ключей с префиксом в лексикографическом порядке, обход прекращается, когда fn возвращает false
+func (t *radixTree) walkPrefix(prefix string, fn func(key string) bool) {
+	n := &t.root
+	path := ""
+	for prefix != "" {
+		_, child := n.child(prefix[0])
+		if child == nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(prefix, child.label):
+			prefix = prefix[len(child.label):]
+		case strings.HasPrefix(child.label, prefix):
+			prefix = ""
+		default:
+			return
+		}
+		path += child.label
+		n = child
+	}
+	n.walk(path, fn)
+}
+
+// обход ключей поддерева в лексикографическом порядке
+func (n *radixNode) walk(path string, fn func(key string) bool) bool {
+	if n.leaf && !fn(path) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(path+child.label, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// длина общего префикса двух строк
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,93 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// интерфейс кеша с упреждающим обновлением записей
+type IRefreshCache interface {
+	// SetRefreshAhead добавление или замена правила упреждающего обновления с именем name
+	SetRefreshAhead(name string, rule RefreshRule[string, interface{}])
+	// RemoveRefreshAhead удаление правила упреждающего обновления
+	RemoveRefreshAhead(name string) bool
+}
+
+// ф-я загрузки значения по ключу, возвращает значение и его TTL
+type KeyLoaderFunc[K comparable, V any] func(ctx context.Context, key K) (value V, ttl time.Duration, err error)
+
+// правило упреждающего обновления: чтение ключа, подходящего под правило, в последней доле
+// Threshold его TTL возвращает текущее значение и запускает одну фоновую перезагрузку ключа.
+// Результат перезагрузки отбрасывается, если запись за это время изменили или удалили.
+type RefreshRule[K comparable, V any] struct {
+	Match     func(key K) bool    // отбор ключей, nil - все ключи
+	Loader    KeyLoaderFunc[K, V] // загрузка нового значения
+	Threshold float64             // доля TTL до истечения, например 0.2 - последние 20% TTL
+}
+
+// именованное правило упреждающего обновления
+type namedRefreshRule[K comparable, V any] struct {
+	name string
+	rule RefreshRule[K, V]
+}
+
+// добавление или замена правила упреждающего обновления с именем name.
+// Если ключ подходит под несколько правил, применяется добавленное раньше.
+func (lru *LRUCache[K, V]) SetRefreshAhead(name string, rule RefreshRule[K, V]) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	for i := range lru.refreshRules {
+		if lru.refreshRules[i].name == name {
+			lru.refreshRules[i].rule = rule
+			return
+		}
+	}
+	lru.refreshRules = append(lru.refreshRules, namedRefreshRule[K, V]{name, rule})
+}
+
+// удаление правила упреждающего обновления, возвращает false, если правила нет
+func (lru *LRUCache[K, V]) RemoveRefreshAhead(name string) bool {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	for i := range lru.refreshRules {
+		if lru.refreshRules[i].name == name {
+			lru.refreshRules = append(lru.refreshRules[:i], lru.refreshRules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// правило упреждающего обновления для ключа. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) refreshRule(key K) (RefreshRule[K, V], bool) {
+	for _, named := range lru.refreshRules {
+		if named.rule.Loader != nil && (named.rule.Match == nil || named.rule.Match(key)) {
+			return named.rule, true
+		}
+	}
+	return RefreshRule[K, V]{}, false
+}
+
+// запуск фоновой перезагрузки записи, если она попала в порог обновления своего правила
+// и ещё не загружается. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) maybeRefresh(ctx context.Context, pair *Pair[K, V]) {
+	rule, ok := lru.refreshRule(pair.key)
+	if !ok || pair.ttl <= 0 {
+		return
+	}
+	threshold := time.Duration(float64(pair.ttl) * rule.Threshold)
+	if pair.expiresAt.Sub(lru.now()) > threshold {
+		return
+	}
+
+	lru.startLoad(context.WithoutCancel(ctx), pair.key, rule.keyLoader(pair.key))
+}
+
+// загрузчик значения конкретного ключа
+func (rule RefreshRule[K, V]) keyLoader(key K) LoaderFunc[V] {
+	return func(ctx context.Context) (V, time.Duration, error) {
+		return rule.Loader(ctx, key)
+	}
+}
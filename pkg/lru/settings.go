@@ -0,0 +1,125 @@
+package lru
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// источник текущего времени для сроков действия записей
+type Clock interface {
+	Now() time.Time
+}
+
+// настройки кеша, задаваемые при создании
+type cacheConfig struct {
+	defaultTTL  time.Duration
+	negativeTTL time.Duration
+	maxTTL      time.Duration
+	jitter      float64
+	clock       Clock
+}
+
+// необязательный параметр создания кеша
+type Option func(*cacheConfig)
+
+// TTL записей, добавленных с ttl = 0. Без него такие записи бессрочные.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(c *cacheConfig) {
+		c.defaultTTL = ttl
+	}
+}
+
+// TTL записей об отсутствии ключа, добавленных с ttl = 0. Обычно короче TTL по умолчанию,
+// чтобы появившийся в источнике ключ быстрее стал доступен. Без него используется TTL по умолчанию.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *cacheConfig) {
+		c.negativeTTL = ttl
+	}
+}
+
+// максимальный TTL записей: больший TTL и бессрочные записи ограничиваются им
+func WithMaxTTL(ttl time.Duration) Option {
+	return func(c *cacheConfig) {
+		c.maxTTL = ttl
+	}
+}
+
+// случайное сокращение TTL до доли fraction от него, чтобы записи, добавленные одновременно,
+// не истекали одновременно. TTL при этом никогда не превышает заданный.
+func WithTTLJitter(fraction float64) Option {
+	return func(c *cacheConfig) {
+		c.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// источник текущего времени вместо системных часов. Фоновая очистка запускается по системному таймеру,
+// но просроченность записей определяет по clock.
+func WithClock(clock Clock) Option {
+	return func(c *cacheConfig) {
+		c.clock = clock
+	}
+}
+
+// необязательный параметр создания кеша со строковыми ключами: настройка Option
+// или обработчик удаления записей WithEvictionListener
+type LRUOption interface {
+	applyLRU(lru *LRUCache[string, interface{}])
+}
+
+func (opt Option) applyLRU(lru *LRUCache[string, interface{}]) {
+	opt(&lru.config)
+}
+
+// обработчик удаления записей, заданный при создании кеша
+type evictionListenerOption EvictionListener[string, interface{}]
+
+func (opt evictionListenerOption) applyLRU(lru *LRUCache[string, interface{}]) {
+	lru.listeners = append(lru.listeners, EvictionListener[string, interface{}](opt))
+}
+
+// обработчик удаления записей, как при регистрации через OnEvict. Принимается конструкторами кешей
+// со строковыми ключами; кешу с другими типами ключа и значения обработчик задаётся через OnEvict.
+func WithEvictionListener(listener EvictionListener[string, interface{}]) LRUOption {
+	return evictionListenerOption(listener)
+}
+
+// применение параметров создания к новому кешу со строковыми ключами
+func withLRUOptions(lru *LRUCache[string, interface{}], opts []LRUOption) *LRUCache[string, interface{}] {
+	for _, opt := range opts {
+		opt.applyLRU(lru)
+	}
+	return lru
+}
+
+// применение настроек к новому кешу
+func (lru *LRUCache[K, V]) configure(opts []Option) {
+	for _, opt := range opts {
+		opt(&lru.config)
+	}
+}
+
+// текущее время по часам кеша
+func (lru *LRUCache[K, V]) now() time.Time {
+	if lru.config.clock != nil {
+		return lru.config.clock.Now()
+	}
+	return time.Now()
+}
+
+// TTL записи с учётом настроек кеша: 0 заменяется TTL по умолчанию или NoExpiration,
+// TTL ограничивается максимальным, затем положительный TTL сокращается на случайную долю
+func (lru *LRUCache[K, V]) resolveTTL(ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		ttl = lru.config.defaultTTL
+		if ttl == 0 {
+			ttl = NoExpiration
+		}
+	}
+	if lru.config.maxTTL > 0 && (ttl == NoExpiration || ttl > lru.config.maxTTL) {
+		ttl = lru.config.maxTTL
+	}
+	if ttl > 0 && lru.config.jitter > 0 {
+		ttl -= time.Duration(rand.Float64() * lru.config.jitter * float64(ttl))
+	}
+	return ttl
+}
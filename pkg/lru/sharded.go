@@ -0,0 +1,417 @@
+package lru
+
+import (
+	"context"
+	"hash/fnv"
+	"iter"
+	"slices"
+	"time"
+)
+
+// шардированный LRU-кеш: ключи распределяются по независимым шардам, у каждого свой мьютекс
+type ShardedLRUCache struct {
+	shards []*LRUCache[string, interface{}]
+}
+
+// создание шардированного LRU-кеша, вместимость делится между шардами поровну, настройки применяются к каждому шарду
+func NewShardedLRUCache(capacity int, shardsCount int, opts ...LRUOption) *ShardedLRUCache {
+	return NewShardedLRUCacheFunc(capacity, shardsCount, func(capacity int) *LRUCache[string, interface{}] {
+		return NewLRUCache(capacity, opts...)
+	})
+}
+
+// создание шардированного кеша, шарды создаются ф-ей newShard со своей долей вместимости
+func NewShardedLRUCacheFunc(capacity int, shardsCount int, newShard func(capacity int) *LRUCache[string, interface{}]) *ShardedLRUCache {
+	shardsCount = ShardsCount(capacity, shardsCount)
+
+	shards := make([]*LRUCache[string, interface{}], shardsCount)
+	for i := range shards {
+		shardCapacity := capacity / shardsCount
+		if i < capacity%shardsCount {
+			shardCapacity++
+		}
+		shards[i] = newShard(shardCapacity)
+	}
+
+	return &ShardedLRUCache{
+		shards: shards,
+	}
+}
+
+// фактическое количество шардов кеша: не меньше одного и не больше вместимости
+func ShardsCount(capacity int, shardsCount int) int {
+	if shardsCount < 1 {
+		shardsCount = 1
+	}
+	if shardsCount > capacity && capacity > 0 {
+		shardsCount = capacity
+	}
+	return shardsCount
+}
+
+// выбор шарда по хешу ключа
+func (s *ShardedLRUCache) shard(key string) *LRUCache[string, interface{}] {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// добавление значения в кеш по ключу
+func (s *ShardedLRUCache) Put(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return s.shard(key).Put(ctx, key, value, ttl)
+}
+
+// получение значения по ключу из кеша
+func (s *ShardedLRUCache) Get(ctx context.Context, key string) (interface{}, time.Time, error) {
+	return s.shard(key).Get(ctx, key)
+}
+
+// добавление значения в кеш по ключу с дополнительными параметрами
+func (s *ShardedLRUCache) PutWithOptions(ctx context.Context, key string, value interface{}, ttl time.Duration, opts ...PutOption) error {
+	return s.shard(key).PutWithOptions(ctx, key, value, ttl, opts...)
+}
+
+// запись в кеш факта отсутствия ключа
+func (s *ShardedLRUCache) PutNegative(ctx context.Context, key string, ttl time.Duration) error {
+	return s.shard(key).PutNegative(ctx, key, ttl)
+}
+
+// получение записи по ключу с учётом правил упреждающего обновления
+func (s *ShardedLRUCache) GetEntry(ctx context.Context, key string) (Entry[interface{}], error) {
+	return s.shard(key).GetEntry(ctx, key)
+}
+
+// получение значения по ключу с загрузкой при промахе
+func (s *ShardedLRUCache) GetOrLoad(ctx context.Context, key string, loader LoaderFunc[interface{}]) (Entry[interface{}], error) {
+	return s.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+// получение всего наполнения кэша со всех шардов. Порядок определён только внутри шарда.
+func (s *ShardedLRUCache) GetAll(ctx context.Context) ([]string, []interface{}, error) {
+	keys := make([]string, 0)
+	values := make([]interface{}, 0)
+
+	for _, shard := range s.shards {
+		shardKeys, shardValues, err := shard.GetAll(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, shardKeys...)
+		values = append(values, shardValues...)
+	}
+
+	return keys, values, nil
+}
+
+// удаление элемента по ключу
+func (s *ShardedLRUCache) Evict(ctx context.Context, key string) (interface{}, error) {
+	return s.shard(key).Evict(ctx, key)
+}
+
+// очищение всех шардов кеша
+func (s *ShardedLRUCache) EvictAll(ctx context.Context) error {
+	for _, shard := range s.shards {
+		err := shard.EvictAll(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// запуск фоновой очистки просроченных записей во всех шардах
+func (s *ShardedLRUCache) StartJanitor(interval time.Duration) {
+	for _, shard := range s.shards {
+		shard.StartJanitor(interval)
+	}
+}
+
+// остановка фоновой очистки во всех шардах
+func (s *ShardedLRUCache) StopJanitor() {
+	for _, shard := range s.shards {
+		shard.StopJanitor()
+	}
+}
+
+// удаление всех просроченных записей во всех шардах
+func (s *ShardedLRUCache) DeleteExpired() int {
+	deleted := 0
+	for _, shard := range s.shards {
+		deleted += shard.DeleteExpired()
+	}
+	return deleted
+}
+
+// регистрация обработчика удаления записей во всех шардах
+func (s *ShardedLRUCache) OnEvict(listener EvictionListener[string, interface{}]) {
+	for _, shard := range s.shards {
+		shard.OnEvict(listener)
+	}
+}
+
+// суммарная статистика всех шардов
+func (s *ShardedLRUCache) Stats() Stats {
+	total := Stats{}
+	for _, shard := range s.shards {
+		stats := shard.Stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Expired += stats.Expired
+		total.CapacityEvictions += stats.CapacityEvictions
+		total.ManualEvictions += stats.ManualEvictions
+		total.Len += stats.Len
+		total.Capacity += stats.Capacity
+	}
+	return total
+}
+
+// обнуление счётчиков статистики всех шардов
+func (s *ShardedLRUCache) ResetStats() {
+	for _, shard := range s.shards {
+		shard.ResetStats()
+	}
+}
+
+// добавление или замена правила упреждающего обновления во всех шардах
+func (s *ShardedLRUCache) SetRefreshAhead(name string, rule RefreshRule[string, interface{}]) {
+	for _, shard := range s.shards {
+		shard.SetRefreshAhead(name, rule)
+	}
+}
+
+// удаление правила упреждающего обновления во всех шардах
+func (s *ShardedLRUCache) RemoveRefreshAhead(name string) bool {
+	removed := false
+	for _, shard := range s.shards {
+		removed = shard.RemoveRefreshAhead(name) || removed
+	}
+	return removed
+}
+
+// получение значения по ключу без изменения его позиции
+func (s *ShardedLRUCache) Peek(ctx context.Context, key string) (interface{}, time.Time, error) {
+	return s.shard(key).Peek(ctx, key)
+}
+
+// наличие актуального значения по ключу
+func (s *ShardedLRUCache) Contains(ctx context.Context, key string) bool {
+	return s.shard(key).Contains(ctx, key)
+}
+
+// количество хранимых записей во всех шардах
+func (s *ShardedLRUCache) Len() int {
+	length := 0
+	for _, shard := range s.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+// суммарная вместимость шардов
+func (s *ShardedLRUCache) Capacity() int {
+	capacity := 0
+	for _, shard := range s.shards {
+		capacity += shard.Capacity()
+	}
+	return capacity
+}
+
+// ключи актуальных записей всех шардов. Порядок использования соблюдается только внутри шарда.
+func (s *ShardedLRUCache) Keys(ctx context.Context) []string {
+	keys := make([]string, 0)
+	for _, shard := range s.shards {
+		keys = append(keys, shard.Keys(ctx)...)
+	}
+	return keys
+}
+
+// обход актуальных записей всех шардов по очереди. Порядок и позиция записи определены только внутри шарда.
+func (s *ShardedLRUCache) All(mode IterationMode) iter.Seq2[string, Entry[interface{}]] {
+	return func(yield func(string, Entry[interface{}]) bool) {
+		for _, shard := range s.shards {
+			for key, entry := range shard.All(mode) {
+				if !yield(key, entry) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// разбиение позиций ключей по шардам, каждый шард обрабатывает свою часть пакета под одним захватом мьютекса
+func (s *ShardedLRUCache) group(keys []string) map[*LRUCache[string, interface{}]][]int {
+	groups := make(map[*LRUCache[string, interface{}]][]int)
+	for i, key := range keys {
+		shard := s.shard(key)
+		groups[shard] = append(groups[shard], i)
+	}
+	return groups
+}
+
+// добавление нескольких значений, по одному захвату мьютекса на шард
+func (s *ShardedLRUCache) PutMany(ctx context.Context, items []BatchItem[string, interface{}]) []error {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+
+	errs := make([]error, len(items))
+	for shard, positions := range s.group(keys) {
+		shardItems := make([]BatchItem[string, interface{}], len(positions))
+		for i, pos := range positions {
+			shardItems[i] = items[pos]
+		}
+		for i, err := range shard.PutMany(ctx, shardItems) {
+			errs[positions[i]] = err
+		}
+	}
+	return errs
+}
+
+// получение нескольких записей, по одному захвату мьютекса на шард
+func (s *ShardedLRUCache) GetMany(ctx context.Context, keys []string) ([]Entry[interface{}], []error) {
+	entries := make([]Entry[interface{}], len(keys))
+	errs := make([]error, len(keys))
+	for shard, positions := range s.group(keys) {
+		shardKeys := make([]string, len(positions))
+		for i, pos := range positions {
+			shardKeys[i] = keys[pos]
+		}
+		shardEntries, shardErrs := shard.GetMany(ctx, shardKeys)
+		for i, pos := range positions {
+			entries[pos], errs[pos] = shardEntries[i], shardErrs[i]
+		}
+	}
+	return entries, errs
+}
+
+// удаление нескольких записей, по одному захвату мьютекса на шард
+func (s *ShardedLRUCache) EvictMany(ctx context.Context, keys []string) ([]interface{}, []error) {
+	values := make([]interface{}, len(keys))
+	errs := make([]error, len(keys))
+	for shard, positions := range s.group(keys) {
+		shardKeys := make([]string, len(positions))
+		for i, pos := range positions {
+			shardKeys[i] = keys[pos]
+		}
+		shardValues, shardErrs := shard.EvictMany(ctx, shardKeys)
+		for i, pos := range positions {
+			values[pos], errs[pos] = shardValues[i], shardErrs[i]
+		}
+	}
+	return values, errs
+}
+
+// добавление значения, только если по ключу нет актуальной записи
+func (s *ShardedLRUCache) PutIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, opts ...PutOption) (uint64, error) {
+	return s.shard(key).PutIfAbsent(ctx, key, value, ttl, opts...)
+}
+
+// замена значения, только если по ключу есть актуальная запись
+func (s *ShardedLRUCache) Replace(ctx context.Context, key string, value interface{}, ttl time.Duration, opts ...PutOption) (uint64, error) {
+	return s.shard(key).Replace(ctx, key, value, ttl, opts...)
+}
+
+// замена значения, только если версия актуальной записи совпадает с ожидаемой
+func (s *ShardedLRUCache) CompareAndSwap(ctx context.Context, key string, expectedVersion uint64, value interface{}, ttl time.Duration, opts ...PutOption) (uint64, error) {
+	return s.shard(key).CompareAndSwap(ctx, key, expectedVersion, value, ttl, opts...)
+}
+
+// запись значения при соблюдении условия
+func (s *ShardedLRUCache) PutIf(ctx context.Context, key string, value interface{}, ttl time.Duration, cond Precondition, opts ...PutOption) (uint64, error) {
+	return s.shard(key).PutIf(ctx, key, value, ttl, cond, opts...)
+}
+
+// удаление записи при соблюдении условия
+func (s *ShardedLRUCache) EvictIf(ctx context.Context, key string, cond Precondition) (interface{}, error) {
+	return s.shard(key).EvictIf(ctx, key, cond)
+}
+
+// атомарное изменение значения по ключу
+func (s *ShardedLRUCache) Update(ctx context.Context, key string, fn UpdateFunc[interface{}]) (Entry[interface{}], error) {
+	return s.shard(key).Update(ctx, key, fn)
+}
+
+// увеличение целого значения по ключу
+func (s *ShardedLRUCache) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return s.shard(key).Incr(ctx, key, delta, ttl)
+}
+
+// увеличение целого значения по ключу при соблюдении условия
+func (s *ShardedLRUCache) IncrIf(ctx context.Context, key string, delta int64, ttl time.Duration, cond Precondition) (int64, error) {
+	return s.shard(key).IncrIf(ctx, key, delta, ttl, cond)
+}
+
+// уменьшение целого значения по ключу
+func (s *ShardedLRUCache) Decr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return s.shard(key).Decr(ctx, key, delta, ttl)
+}
+
+// удаление всех записей с тегом во всех шардах одновременно: чтения не видят группу удалённой частично
+func (s *ShardedLRUCache) EvictByTag(ctx context.Context, tag string) int {
+	// мьютексы шардов захватываются по порядку индексов, обработчики удалений вызываются после снятия всех
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+	}
+	evicted := 0
+	for _, shard := range s.shards {
+		evicted += shard.evictTag(tag)
+	}
+	notify := make([]func(), 0, len(s.shards))
+	for _, shard := range s.shards {
+		notify = append(notify, shard.release())
+	}
+	for _, fn := range notify {
+		fn()
+	}
+	return evicted
+}
+
+// ключи актуальных записей с префиксом со всех шардов в лексикографическом порядке
+func (s *ShardedLRUCache) KeysWithPrefix(ctx context.Context, prefix string) []string {
+	keys := make([]string, 0)
+	for _, shard := range s.shards {
+		keys = append(keys, shard.KeysWithPrefix(ctx, prefix)...)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// удаление всех записей с префиксом во всех шардах. Удаление атомарно в пределах шарда.
+func (s *ShardedLRUCache) EvictPrefix(ctx context.Context, prefix string) int {
+	evicted := 0
+	for _, shard := range s.shards {
+		evicted += shard.EvictPrefix(ctx, prefix)
+	}
+	return evicted
+}
+
+// установка TTL записи от текущего момента
+func (s *ShardedLRUCache) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	return s.shard(key).Touch(ctx, key, ttl)
+}
+
+// установка времени истечения записи
+func (s *ShardedLRUCache) ExpireAt(ctx context.Context, key string, expiresAt time.Time) error {
+	return s.shard(key).ExpireAt(ctx, key, expiresAt)
+}
+
+// установка TTL записи при соблюдении условия
+func (s *ShardedLRUCache) TouchIf(ctx context.Context, key string, ttl time.Duration, cond Precondition) error {
+	return s.shard(key).TouchIf(ctx, key, ttl, cond)
+}
+
+// установка времени истечения записи при соблюдении условия
+func (s *ShardedLRUCache) ExpireAtIf(ctx context.Context, key string, expiresAt time.Time, cond Precondition) error {
+	return s.shard(key).ExpireAtIf(ctx, key, expiresAt, cond)
+}
+
+// снятие срока действия записи
+func (s *ShardedLRUCache) Persist(ctx context.Context, key string) error {
+	return s.shard(key).Persist(ctx, key)
+}
+
+// оставшееся время действия записи
+func (s *ShardedLRUCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return s.shard(key).TTL(ctx, key)
+}
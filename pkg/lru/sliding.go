@@ -0,0 +1,29 @@
+package lru
+
+import (
+	"time"
+)
+
+// скользящее истечение: каждое успешное чтение продлевает запись на её TTL от момента чтения.
+// maxLifetime > 0 ограничивает время жизни записи от момента записи, чтобы часто читаемая запись не жила бесконечно.
+func WithSliding(maxLifetime time.Duration) PutOption {
+	return func(o *putOptions) {
+		o.sliding = true
+		o.maxLifetime = maxLifetime
+	}
+}
+
+// продление записи со скользящим истечением после успешного чтения. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) slide(pair *Pair[K, V], now time.Time) {
+	if !pair.sliding || pair.ttl <= 0 {
+		return
+	}
+	expiresAt := now.Add(pair.ttl)
+	if !pair.maxExpiresAt.IsZero() && expiresAt.After(pair.maxExpiresAt) {
+		expiresAt = pair.maxExpiresAt
+	}
+	if expiresAt.After(pair.expiresAt) {
+		pair.expiresAt = expiresAt
+		lru.fixExpiry(pair)
+	}
+}
@@ -0,0 +1,68 @@
+package lru
+
+import (
+	"sync/atomic"
+)
+
+// интерфейс кеша, собирающего статистику обращений
+type IStatsCache interface {
+	// Stats текущая статистика кеша
+	Stats() Stats
+	// ResetStats обнуление счётчиков статистики
+	ResetStats()
+}
+
+// статистика кеша
+type Stats struct {
+	Hits              uint64 // успешные чтения
+	Misses            uint64 // чтения отсутствующих и просроченных ключей
+	Expired           uint64 // чтения просроченных ключей
+	CapacityEvictions uint64 // вытеснения из-за нехватки места
+	ManualEvictions   uint64 // удаления вызовом Evict
+	Len               int    // текущее количество записей
+	Capacity          int    // максимальное количество записей, 0 - без ограничения
+}
+
+// доля успешных чтений среди всех чтений
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// счётчики статистики, изменяются атомарно без захвата мьютекса кеша
+type statsCounters struct {
+	hits              atomic.Uint64
+	misses            atomic.Uint64
+	expired           atomic.Uint64
+	capacityEvictions atomic.Uint64
+	manualEvictions   atomic.Uint64
+}
+
+// текущая статистика кеша
+func (lru *LRUCache[K, V]) Stats() Stats {
+	lru.mu.Lock()
+	length := len(lru.cache)
+	lru.mu.Unlock()
+
+	return Stats{
+		Hits:              lru.stats.hits.Load(),
+		Misses:            lru.stats.misses.Load(),
+		Expired:           lru.stats.expired.Load(),
+		CapacityEvictions: lru.stats.capacityEvictions.Load(),
+		ManualEvictions:   lru.stats.manualEvictions.Load(),
+		Len:               length,
+		Capacity:          lru.Capacity(),
+	}
+}
+
+// обнуление счётчиков статистики
+func (lru *LRUCache[K, V]) ResetStats() {
+	lru.stats.hits.Store(0)
+	lru.stats.misses.Store(0)
+	lru.stats.expired.Store(0)
+	lru.stats.capacityEvictions.Store(0)
+	lru.stats.manualEvictions.Store(0)
+}
@@ -0,0 +1,63 @@
+package lru
+
+import (
+	"context"
+)
+
+// интерфейс кеша с групповым удалением записей по тегу
+type ITagCache interface {
+	// EvictByTag удаление всех записей с тегом, возвращает количество удалённых записей
+	EvictByTag(ctx context.Context, tag string) int
+}
+
+// теги записи, по которым её можно удалить вместе с другими записями группы. Повторная запись значения
+// без тегов снимает прежние теги.
+func WithTags(tags ...string) PutOption {
+	return func(o *putOptions) {
+		o.tags = append([]string(nil), tags...)
+	}
+}
+
+// добавление записи в индекс тегов. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) tag(pair *Pair[K, V]) {
+	if len(pair.tags) > 0 && lru.tags == nil {
+		lru.tags = make(map[string]map[K]struct{})
+	}
+	for _, tag := range pair.tags {
+		keys, ok := lru.tags[tag]
+		if !ok {
+			keys = make(map[K]struct{})
+			lru.tags[tag] = keys
+		}
+		keys[pair.key] = struct{}{}
+	}
+}
+
+// удаление записи из индекса тегов. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) untag(pair *Pair[K, V]) {
+	for _, tag := range pair.tags {
+		keys := lru.tags[tag]
+		delete(keys, pair.key)
+		if len(keys) == 0 {
+			delete(lru.tags, tag)
+		}
+	}
+}
+
+// удаление всех записей с тегом под одним захватом мьютекса, возвращает количество удалённых записей
+func (lru *LRUCache[K, V]) EvictByTag(ctx context.Context, tag string) int {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	return lru.evictTag(tag)
+}
+
+// удаление всех записей с тегом, возвращает количество удалённых записей. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) evictTag(tag string) int {
+	keys := lru.tags[tag]
+	evicted := len(keys)
+	for key := range keys {
+		lru.removePair(lru.cache[key], EvictionReasonManual)
+	}
+	return evicted
+}
@@ -0,0 +1,253 @@
+package lru
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// максимальное значение счётчика частоты в count-min sketch
+const sketchMaxCount = 15
+
+// вероятностная оценка частоты обращений к ключам (count-min sketch) с периодическим старением:
+// после заданного числа обращений все счётчики уменьшаются вдвое
+type countMinSketch[K comparable] struct {
+	seed      maphash.Seed
+	rows      [4][]uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+// создание count-min sketch для оценки частот примерно size ключей
+func newCountMinSketch[K comparable](size int) *countMinSketch[K] {
+	width := 256
+	for width < 4*size {
+		width <<= 1
+	}
+
+	s := &countMinSketch[K]{
+		seed:    maphash.MakeSeed(),
+		mask:    uint64(width - 1),
+		resetAt: 10 * width,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// множители для независимого перемешивания хеша в каждой строке
+var sketchRowSeeds = [4]uint64{0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9, 0x94d049bb133111eb, 0xd6e8feb86659fd93}
+
+// индекс счётчика ключа в строке row
+func (s *countMinSketch[K]) index(hash uint64, row int) uint64 {
+	h := hash * sketchRowSeeds[row]
+	h ^= h >> 32
+	return h & s.mask
+}
+
+// учёт обращения к ключу
+func (s *countMinSketch[K]) increment(key K) {
+	hash := maphash.Comparable(s.seed, key)
+	for i := range s.rows {
+		idx := s.index(hash, i)
+		if s.rows[i][idx] < sketchMaxCount {
+			s.rows[i][idx]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.age()
+	}
+}
+
+// оценка частоты обращений к ключу
+func (s *countMinSketch[K]) estimate(key K) uint8 {
+	hash := maphash.Comparable(s.seed, key)
+	freq := uint8(sketchMaxCount)
+	for i := range s.rows {
+		freq = min(freq, s.rows[i][s.index(hash, i)])
+	}
+	return freq
+}
+
+// старение: уменьшение всех счётчиков вдвое
+func (s *countMinSketch[K]) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+// сегменты W-TinyLFU
+const (
+	tinyLFUWindow    = iota // окно допуска для новых ключей
+	tinyLFUProbation        // испытательный сегмент основной области
+	tinyLFUProtected        // защищённый сегмент основной области
+)
+
+// положение ключа в одном из сегментов W-TinyLFU
+type tinyLFUEntry[K comparable] struct {
+	key     K
+	segment int
+}
+
+// стратегия W-TinyLFU: новые ключи попадают в небольшое LRU-окно, вытесняемый из окна ключ
+// допускается в сегментированную основную область, только если по оценке count-min sketch
+// он используется чаще, чем кандидат на вытеснение из основной области
+type tinyLFUPolicy[K comparable] struct {
+	windowCap    int
+	mainCap      int
+	protectedCap int
+	sketch       *countMinSketch[K]
+	segments     [3]*list.List
+	elements     map[K]*list.Element
+}
+
+// создание стратегии W-TinyLFU для кеша вместимостью capacity записей.
+// Окно занимает 1% вместимости, защищённый сегмент - 80% основной области.
+// При capacity <= 0 основная область не ограничена и фильтр допуска не работает.
+func NewTinyLFUPolicy[K comparable](capacity int) EvictionPolicy[K] {
+	p := &tinyLFUPolicy[K]{
+		windowCap: 1,
+		mainCap:   -1,
+		sketch:    newCountMinSketch[K](capacity),
+		elements:  make(map[K]*list.Element),
+	}
+	if capacity > 0 {
+		p.windowCap = max(capacity/100, 1)
+		p.mainCap = max(capacity-p.windowCap, 0)
+		p.protectedCap = p.mainCap * 80 / 100
+	}
+	for i := range p.segments {
+		p.segments[i] = list.New()
+	}
+	return p
+}
+
+// создание W-TinyLFU-кеша со строковыми ключами, удовлетворяющего ILRUCache
+func NewTinyLFUCache(capacity int, opts ...LRUOption) *LRUCache[string, interface{}] {
+	return withLRUOptions(NewWithPolicy[string, interface{}](capacity, NewTinyLFUPolicy[string](capacity)), opts)
+}
+
+func (p *tinyLFUPolicy[K]) OnInsert(key K) {
+	p.sketch.increment(key)
+	p.elements[key] = p.segments[tinyLFUWindow].PushFront(&tinyLFUEntry[K]{key: key, segment: tinyLFUWindow})
+
+	// пока основная область не заполнена, вытесняемые из окна ключи переходят в неё без конкуренции
+	for p.segments[tinyLFUWindow].Len() > p.windowCap && !p.mainFull() {
+		p.move(p.segments[tinyLFUWindow].Back(), tinyLFUProbation)
+	}
+}
+
+func (p *tinyLFUPolicy[K]) OnAccess(key K) {
+	p.sketch.increment(key)
+
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+	switch element.Value.(*tinyLFUEntry[K]).segment {
+	case tinyLFUWindow:
+		p.segments[tinyLFUWindow].MoveToFront(element)
+	case tinyLFUProbation:
+		p.move(element, tinyLFUProtected)
+		if p.mainCap >= 0 && p.segments[tinyLFUProtected].Len() > p.protectedCap {
+			p.move(p.segments[tinyLFUProtected].Back(), tinyLFUProbation)
+		}
+	case tinyLFUProtected:
+		p.segments[tinyLFUProtected].MoveToFront(element)
+	}
+}
+
+// частота учитывает и промахи, чтобы часто запрашиваемый отсутствующий ключ прошёл фильтр допуска
+func (p *tinyLFUPolicy[K]) OnMiss(key K) {
+	p.sketch.increment(key)
+}
+
+func (p *tinyLFUPolicy[K]) OnRemove(key K) {
+	element, ok := p.elements[key]
+	if !ok {
+		return
+	}
+	p.remove(element)
+}
+
+func (p *tinyLFUPolicy[K]) Evict() (K, bool) {
+	window := p.segments[tinyLFUWindow]
+	victim := p.segments[tinyLFUProbation].Back()
+	if victim == nil {
+		victim = p.segments[tinyLFUProtected].Back()
+	}
+
+	var evicted *list.Element
+	switch candidate := window.Back(); {
+	case candidate == nil && victim == nil:
+		var zero K
+		return zero, false
+	case candidate == nil:
+		evicted = victim
+	case victim == nil:
+		evicted = candidate
+	case window.Len() < p.windowCap:
+		evicted = victim
+	default:
+		// окно заполнено и новый ключ вытеснит из него кандидата. Фильтр допуска: кандидат
+		// вытесняет жертву из основной области, только если обращения к нему происходят чаще
+		candidateKey := candidate.Value.(*tinyLFUEntry[K]).key
+		victimKey := victim.Value.(*tinyLFUEntry[K]).key
+		if p.sketch.estimate(candidateKey) > p.sketch.estimate(victimKey) {
+			p.move(candidate, tinyLFUProbation)
+			evicted = victim
+		} else {
+			evicted = candidate
+		}
+	}
+
+	key := evicted.Value.(*tinyLFUEntry[K]).key
+	p.remove(evicted)
+	return key, true
+}
+
+func (p *tinyLFUPolicy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.elements))
+	for _, segment := range []int{tinyLFUProtected, tinyLFUWindow, tinyLFUProbation} {
+		for e := p.segments[segment].Front(); e != nil; e = e.Next() {
+			keys = append(keys, e.Value.(*tinyLFUEntry[K]).key)
+		}
+	}
+	return keys
+}
+
+func (p *tinyLFUPolicy[K]) Reset() {
+	for _, segment := range p.segments {
+		segment.Init()
+	}
+	p.elements = make(map[K]*list.Element)
+}
+
+// заполнена ли основная область
+func (p *tinyLFUPolicy[K]) mainFull() bool {
+	if p.mainCap < 0 {
+		return false
+	}
+	return p.segments[tinyLFUProbation].Len()+p.segments[tinyLFUProtected].Len() >= p.mainCap
+}
+
+// перенос ключа в начало сегмента to
+func (p *tinyLFUPolicy[K]) move(element *list.Element, to int) {
+	entry := element.Value.(*tinyLFUEntry[K])
+	p.segments[entry.segment].Remove(element)
+	entry.segment = to
+	p.elements[entry.key] = p.segments[to].PushFront(entry)
+}
+
+// удаление ключа из сегмента
+func (p *tinyLFUPolicy[K]) remove(element *list.Element) {
+	entry := element.Value.(*tinyLFUEntry[K])
+	p.segments[entry.segment].Remove(element)
+	delete(p.elements, entry.key)
+}
@@ -0,0 +1,21 @@
+package lru
+
+import (
+	"time"
+)
+
+// допустимое время простоя записи: запись истекает, если её не читали дольше tti, даже если TTL ещё не истёк.
+// Запись значения и каждое успешное чтение начинают отсчёт заново.
+func WithTTI(tti time.Duration) PutOption {
+	return func(o *putOptions) {
+		o.tti = tti
+	}
+}
+
+// фиксация момента успешного чтения записи. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) markAccess(pair *Pair[K, V], now time.Time) {
+	pair.lastAccess = now
+	if pair.tti > 0 {
+		lru.fixExpiry(pair)
+	}
+}
@@ -0,0 +1,115 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TTL бессрочной записи: запись не истекает, пока её не удалят или не вытеснят.
+// Время истечения такой записи - нулевое time.Time.
+const NoExpiration time.Duration = -1
+
+// интерфейс кеша с управлением сроком действия записей
+type ITTLCache interface {
+	// Touch установка нового TTL записи от текущего момента
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	// ExpireAt установка времени истечения записи
+	ExpireAt(ctx context.Context, key string, expiresAt time.Time) error
+	// Persist снятие срока действия записи
+	Persist(ctx context.Context, key string) error
+	// TTL оставшееся время действия записи, NoExpiration для бессрочной
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
+// установка TTL актуальной записи от текущего момента без изменения значения, версии и порядка вытеснения.
+// TTL приводится по настройкам кеша, как при Put; NoExpiration делает запись бессрочной.
+func (lru *LRUCache[K, V]) Touch(ctx context.Context, key K, ttl time.Duration) error {
+	return lru.TouchIf(ctx, key, ttl, Precondition{})
+}
+
+// установка TTL актуальной записи по правилам Touch при соблюдении условия cond, иначе ErrPrecondition
+func (lru *LRUCache[K, V]) TouchIf(ctx context.Context, key K, ttl time.Duration, cond Precondition) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if err := lru.checkPrecondition(key, cond); err != nil {
+		return err
+	}
+	pair, ok := lru.present(key)
+	if !ok {
+		return errors.New(ErrKeyNotFound)
+	}
+	ttl = lru.resolveTTL(ttl)
+	var expiresAt time.Time
+	if ttl != NoExpiration {
+		expiresAt = lru.now().Add(ttl)
+	}
+	lru.setExpiry(pair, expiresAt, ttl)
+	return nil
+}
+
+// установка времени истечения актуальной записи, нулевое время делает запись бессрочной.
+// Время истечения ограничивается максимальным TTL из настроек кеша.
+func (lru *LRUCache[K, V]) ExpireAt(ctx context.Context, key K, expiresAt time.Time) error {
+	return lru.ExpireAtIf(ctx, key, expiresAt, Precondition{})
+}
+
+// установка времени истечения актуальной записи по правилам ExpireAt при соблюдении условия cond, иначе ErrPrecondition
+func (lru *LRUCache[K, V]) ExpireAtIf(ctx context.Context, key K, expiresAt time.Time, cond Precondition) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if err := lru.checkPrecondition(key, cond); err != nil {
+		return err
+	}
+	pair, ok := lru.present(key)
+	if !ok {
+		return errors.New(ErrKeyNotFound)
+	}
+	if lru.config.maxTTL > 0 {
+		limit := lru.now().Add(lru.config.maxTTL)
+		if expiresAt.IsZero() || expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+	ttl := NoExpiration
+	if !expiresAt.IsZero() {
+		ttl = expiresAt.Sub(lru.now())
+	}
+	lru.setExpiry(pair, expiresAt, ttl)
+	return nil
+}
+
+// снятие срока действия актуальной записи. Ограничения по времени простоя и предельному времени жизни
+// скользящей записи сохраняются.
+func (lru *LRUCache[K, V]) Persist(ctx context.Context, key K) error {
+	return lru.ExpireAt(ctx, key, time.Time{})
+}
+
+// оставшееся время действия актуальной записи с учётом времени простоя, NoExpiration для бессрочной
+func (lru *LRUCache[K, V]) TTL(ctx context.Context, key K) (time.Duration, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	pair, ok := lru.present(key)
+	if !ok {
+		return 0, errors.New(ErrKeyNotFound)
+	}
+	expiry := pair.expiry()
+	if expiry.IsZero() {
+		return NoExpiration, nil
+	}
+	return expiry.Sub(lru.now()), nil
+}
+
+// изменение срока действия записи, срок ограничивается предельным временем жизни скользящей записи.
+// Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) setExpiry(pair *Pair[K, V], expiresAt time.Time, ttl time.Duration) {
+	if !pair.maxExpiresAt.IsZero() && (expiresAt.IsZero() || expiresAt.After(pair.maxExpiresAt)) {
+		expiresAt = pair.maxExpiresAt
+	}
+	pair.expiresAt = expiresAt
+	pair.ttl = ttl
+	lru.fixExpiry(pair)
+}
@@ -0,0 +1,67 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// интерфейс кеша с атомарным изменением значения
+type IUpdateCache interface {
+	// Update атомарное изменение значения по ключу ф-ей fn
+	Update(ctx context.Context, key string, fn UpdateFunc[interface{}]) (Entry[interface{}], error)
+}
+
+// ф-я изменения значения: получает текущее значение и признак его наличия, возвращает новое значение
+// с TTL или признак удаления записи
+type UpdateFunc[V any] func(old V, exists bool) (newValue V, ttl time.Duration, remove bool)
+
+// атомарное изменение значения по ключу. Ф-я fn выполняется вне мьютекса и не блокирует работу
+// с другими ключами; результат записывается, только если запись за это время не изменилась, иначе fn
+// вызывается повторно с новым значением, поэтому она может быть вызвана несколько раз и не должна
+// иметь побочных эффектов. Просроченная запись и запись об отсутствии ключа считаются отсутствующими.
+// Новое значение записывается по правилам Put с TTL из fn, теги, период устаревания и режим истечения
+// существующей записи сохраняются. При удалении возвращается пустая запись.
+func (lru *LRUCache[K, V]) Update(ctx context.Context, key K, fn UpdateFunc[V]) (Entry[V], error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return Entry[V]{}, err
+		}
+
+		lru.mu.Lock()
+		old, version, exists := lru.current(key)
+		lru.mu.Unlock()
+
+		value, ttl, remove := fn(old, exists)
+
+		lru.mu.Lock()
+		if _, current, _ := lru.current(key); current != version {
+			lru.mu.Unlock()
+			continue
+		}
+
+		var entry Entry[V]
+		var err error
+		if remove {
+			if pair, ok := lru.cache[key]; ok {
+				lru.removePair(pair, EvictionReasonManual)
+			}
+		} else {
+			options := putOptions{}
+			if pair, ok := lru.present(key); ok {
+				options = pair.keptOptions()
+			}
+			entry, err = lru.put(key, value, ttl, options)
+		}
+		lru.unlock()
+		return entry, err
+	}
+}
+
+// текущее значение актуальной записи и её версия, для отсутствующей записи версия равна 0. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) current(key K) (V, uint64, bool) {
+	if pair, ok := lru.present(key); ok {
+		return pair.value, pair.version, true
+	}
+	var zero V
+	return zero, 0, false
+}
@@ -0,0 +1,143 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// интерфейс кеша с условной записью по версии записи
+type IVersionedCache interface {
+	// PutIfAbsent запись значения, только если по ключу нет актуальной записи
+	PutIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, opts ...PutOption) (version uint64, err error)
+	// Replace замена значения, только если по ключу есть актуальная запись
+	Replace(ctx context.Context, key string, value interface{}, ttl time.Duration, opts ...PutOption) (version uint64, err error)
+	// CompareAndSwap замена значения, только если версия актуальной записи совпадает с ожидаемой
+	CompareAndSwap(ctx context.Context, key string, expectedVersion uint64, value interface{}, ttl time.Duration, opts ...PutOption) (version uint64, err error)
+}
+
+// интерфейс кеша с операциями, выполняемыми при соблюдении условия на текущую запись
+type IConditionalCache interface {
+	// PutIf запись значения при соблюдении условия, возвращает версию записанного значения
+	PutIf(ctx context.Context, key string, value interface{}, ttl time.Duration, cond Precondition, opts ...PutOption) (version uint64, err error)
+	// EvictIf удаление записи при соблюдении условия
+	EvictIf(ctx context.Context, key string, cond Precondition) (interface{}, error)
+	// TouchIf установка нового TTL записи при соблюдении условия
+	TouchIf(ctx context.Context, key string, ttl time.Duration, cond Precondition) error
+	// ExpireAtIf установка времени истечения записи при соблюдении условия
+	ExpireAtIf(ctx context.Context, key string, expiresAt time.Time, cond Precondition) error
+	// IncrIf увеличение значения по ключу на delta при соблюдении условия
+	IncrIf(ctx context.Context, key string, delta int64, ttl time.Duration, cond Precondition) (int64, error)
+}
+
+// условие на актуальную запись по ключу для условных операций. Пустое условие выполняется всегда.
+// Просроченная запись и запись об отсутствии ключа считаются отсутствующими.
+type Precondition struct {
+	Exists  bool   // запись должна существовать
+	Absent  bool   // записи не должно быть
+	Version uint64 // запись должна существовать с этой версией, 0 - версия не проверяется
+}
+
+// проверка условия по актуальной записи, при несоблюдении возвращается ErrPrecondition
+func (cond Precondition) check(version uint64, ok bool) error {
+	switch {
+	case cond.Absent && ok,
+		(cond.Exists || cond.Version != 0) && !ok,
+		cond.Version != 0 && version != cond.Version:
+		return errors.New(ErrPrecondition)
+	}
+	return nil
+}
+
+// проверка условия по актуальной записи ключа. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) checkPrecondition(key K, cond Precondition) error {
+	_, version, ok := lru.current(key)
+	return cond.check(version, ok)
+}
+
+// актуальная запись по ключу: не просроченная и не запись об отсутствии ключа. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) present(key K) (*Pair[K, V], bool) {
+	pair, ok := lru.cache[key]
+	if !ok || pair.negative || pair.expired(lru.now()) {
+		return nil, false
+	}
+	return pair, true
+}
+
+// условная запись значения: ф-я check проверяет текущую запись под мьютексом. Возвращает версию записанного значения.
+func (lru *LRUCache[K, V]) putIf(key K, value V, ttl time.Duration, opts []PutOption, check func(pair *Pair[K, V], ok bool) error) (uint64, error) {
+	options := putOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	if err := check(lru.present(key)); err != nil {
+		return 0, err
+	}
+	entry, err := lru.put(key, value, ttl, options)
+	return entry.Version, err
+}
+
+// запись значения при соблюдении условия cond, иначе ErrPrecondition. Возвращает версию записанного значения.
+func (lru *LRUCache[K, V]) PutIf(ctx context.Context, key K, value V, ttl time.Duration, cond Precondition, opts ...PutOption) (uint64, error) {
+	return lru.putIf(key, value, ttl, opts, func(pair *Pair[K, V], ok bool) error {
+		if !ok {
+			return cond.check(0, false)
+		}
+		return cond.check(pair.version, true)
+	})
+}
+
+// удаление записи при соблюдении условия cond, иначе ErrPrecondition. Если записи нет, возвращается ErrKeyNotFound.
+func (lru *LRUCache[K, V]) EvictIf(ctx context.Context, key K, cond Precondition) (V, error) {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	var zero V
+	if err := lru.checkPrecondition(key, cond); err != nil {
+		return zero, err
+	}
+	if pair, ok := lru.cache[key]; ok {
+		lru.removePair(pair, EvictionReasonManual)
+		return pair.value, nil
+	}
+	return zero, errors.New(ErrKeyNotFound)
+}
+
+// добавление значения, только если по ключу нет актуальной записи, иначе ErrKeyExists.
+// Просроченная запись и запись об отсутствии ключа считаются отсутствующими.
+func (lru *LRUCache[K, V]) PutIfAbsent(ctx context.Context, key K, value V, ttl time.Duration, opts ...PutOption) (uint64, error) {
+	return lru.putIf(key, value, ttl, opts, func(_ *Pair[K, V], ok bool) error {
+		if ok {
+			return errors.New(ErrKeyExists)
+		}
+		return nil
+	})
+}
+
+// замена значения, только если по ключу есть актуальная запись, иначе ErrKeyNotFound
+func (lru *LRUCache[K, V]) Replace(ctx context.Context, key K, value V, ttl time.Duration, opts ...PutOption) (uint64, error) {
+	return lru.putIf(key, value, ttl, opts, func(_ *Pair[K, V], ok bool) error {
+		if !ok {
+			return errors.New(ErrKeyNotFound)
+		}
+		return nil
+	})
+}
+
+// замена значения, только если версия актуальной записи равна expectedVersion. При отсутствии записи
+// возвращается ErrKeyNotFound, при несовпадении версии - ErrVersionMismatch.
+func (lru *LRUCache[K, V]) CompareAndSwap(ctx context.Context, key K, expectedVersion uint64, value V, ttl time.Duration, opts ...PutOption) (uint64, error) {
+	return lru.putIf(key, value, ttl, opts, func(pair *Pair[K, V], ok bool) error {
+		if !ok {
+			return errors.New(ErrKeyNotFound)
+		}
+		if pair.version != expectedVersion {
+			return errors.New(ErrVersionMismatch)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,67 @@
+package lru
+
+import (
+	"encoding/json"
+)
+
+// ф-я оценки стоимости (веса) записи в кеше
+type Weigher[K comparable, V any] func(key K, value V) int64
+
+// оценка веса записи по размеру значения в формате JSON в байтах
+func JSONWeigher[K comparable, V any](key K, value V) int64 {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 1
+	}
+	return int64(len(data))
+}
+
+// создание LRU-кеша, ограниченного суммарным весом записей maxCost.
+// Если weigher не задан, используется JSONWeigher.
+func NewWeighted[K comparable, V any](capacity int, maxCost int64, weigher Weigher[K, V], opts ...Option) *LRUCache[K, V] {
+	lru := New[K, V](capacity, opts...)
+	lru.SetMaxCost(maxCost, weigher)
+	return lru
+}
+
+// создание LRU-кеша со строковыми ключами, ограниченного суммарным размером значений в байтах
+func NewWeightedLRUCache(capacity int, maxBytes int64, opts ...LRUOption) *LRUCache[string, interface{}] {
+	return withLRUOptions(NewWeighted[string, interface{}](capacity, maxBytes, nil), opts)
+}
+
+// установка ограничения на суммарный вес записей. Если weigher не задан, используется JSONWeigher.
+// Записи, уже находящиеся в кеше, взвешиваются заново, и лишние вытесняются сразу.
+func (lru *LRUCache[K, V]) SetMaxCost(maxCost int64, weigher Weigher[K, V]) {
+	if weigher == nil {
+		weigher = JSONWeigher[K, V]
+	}
+
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	lru.maxCost = maxCost
+	lru.weigher = weigher
+	lru.cost = 0
+	for key, pair := range lru.cache {
+		pair.cost = lru.weigh(key, pair.value)
+		lru.cost += pair.cost
+	}
+	lru.evictOverflow(0, 0)
+}
+
+// вес записи. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) weigh(key K, value V) int64 {
+	if lru.maxCost <= 0 || lru.weigher == nil {
+		return 0
+	}
+	return lru.weigher(key, value)
+}
+
+// превышен ли лимит количества или суммарного веса записей с учётом добавляемых entries записей
+// общим весом cost. Вызывается под мьютексом.
+func (lru *LRUCache[K, V]) overflows(entries int, cost int64) bool {
+	if lru.capacity > 0 && len(lru.cache)+entries > lru.capacity {
+		return true
+	}
+	return lru.maxCost > 0 && lru.cost+cost > lru.maxCost
+}
@@ -0,0 +1,247 @@
+// пакет тестов
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lru"
+)
+
+// тест на единственный вызов загрузчика при одновременных промахах
+func TestGetOrLoadSingleflight(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (interface{}, time.Duration, error) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return "loaded", 1 * time.Hour, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry, err := cache.GetOrLoad(ctx, "key1", loader)
+			if err != nil {
+				t.Errorf("failed to load data with error [%s]", err.Error())
+				return
+			}
+			if entry.Value != "loaded" {
+				t.Errorf("expected [%s], got [%v]", "loaded", entry.Value)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 loader call, got [%d]", calls.Load())
+	}
+
+	value, _, err := cache.Get(ctx, "key1")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+	if value != "loaded" {
+		t.Fatalf("expected [%s], got [%v]", "loaded", value)
+	}
+}
+
+// тест на передачу ошибки загрузчика и независимость от отмены контекста одного из ожидающих
+func TestGetOrLoadErrorAndCancel(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	loadErr := errors.New("upstream unavailable")
+
+	started := make(chan struct{})
+	loader := func(ctx context.Context) (interface{}, time.Duration, error) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, loadErr
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(cancelCtx, "key1", loader)
+		cancelled <- err
+	}()
+
+	<-started
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected [%v], got [%v]", context.Canceled, err)
+	}
+
+	_, err := cache.GetOrLoad(context.Background(), "key1", loader)
+	if err != loadErr {
+		t.Fatalf("expected [%v], got [%v]", loadErr, err)
+	}
+}
+
+// тест на сохранение значения, записанного во время загрузки
+func TestGetOrLoadConcurrentPut(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (interface{}, time.Duration, error) {
+		close(started)
+		<-release
+		return "loaded", 1 * time.Hour, nil
+	}
+
+	loaded := make(chan lru.Entry[interface{}], 1)
+	go func() {
+		entry, err := cache.GetOrLoad(ctx, "key1", loader)
+		if err != nil {
+			t.Errorf("failed to load data with error [%s]", err.Error())
+		}
+		loaded <- entry
+	}()
+
+	<-started
+	cache.Put(ctx, "key1", "put", 1*time.Hour)
+	close(release)
+
+	// ожидающий получает загруженное значение, но в кеше остаётся более новое
+	if entry := <-loaded; entry.Value != "loaded" {
+		t.Fatalf("expected [%s], got [%v]", "loaded", entry.Value)
+	}
+	value, _, err := cache.Get(ctx, "key1")
+	if err != nil || value != "put" {
+		t.Fatalf("expected [%s], got [%v] with error [%v]", "put", value, err)
+	}
+}
+
+// тест на отдачу устаревшего значения во время загрузки нового и при ошибке загрузки
+func TestStaleWhileRevalidate(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	err := cache.PutWithOptions(ctx, "key1", "old", 10*time.Millisecond, lru.WithGracePeriod(1*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to put data with error [%s]", err.Error())
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// без загрузчика запись считается просроченной, но не удаляется
+	_, _, err = cache.Get(ctx, "key1")
+	if err == nil || err.Error() != lru.ErrKeyExpired {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyExpired, err)
+	}
+
+	failing := func(ctx context.Context) (interface{}, time.Duration, error) {
+		return nil, 0, errors.New("upstream unavailable")
+	}
+	entry, err := cache.GetOrLoad(ctx, "key1", failing)
+	if err != nil {
+		t.Fatalf("failed to load data with error [%s]", err.Error())
+	}
+	if entry.Value != "old" || !entry.Stale {
+		t.Fatalf("expected stale value [%s], got [%+v]", "old", entry)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	loaded := make(chan struct{})
+	loader := func(ctx context.Context) (interface{}, time.Duration, error) {
+		defer close(loaded)
+		return "new", 1 * time.Hour, nil
+	}
+	entry, err = cache.GetOrLoad(ctx, "key1", loader)
+	if err != nil {
+		t.Fatalf("failed to load data with error [%s]", err.Error())
+	}
+	if entry.Value != "old" || !entry.Stale {
+		t.Fatalf("expected stale value [%s] after failed reload, got [%+v]", "old", entry)
+	}
+	<-loaded
+	time.Sleep(10 * time.Millisecond)
+
+	entry, err = cache.GetOrLoad(ctx, "key1", loader)
+	if err != nil {
+		t.Fatalf("failed to load data with error [%s]", err.Error())
+	}
+	if entry.Value != "new" || entry.Stale {
+		t.Fatalf("expected fresh value [%s], got [%+v]", "new", entry)
+	}
+}
+
+// тест на запись об отсутствии ключа
+func TestNegativeCaching(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	err := cache.PutNegative(ctx, "key1", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put negative entry with error [%s]", err.Error())
+	}
+	_, _, err = cache.Get(ctx, "key1")
+	if err == nil || err.Error() != lru.ErrKeyNegative {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNegative, err)
+	}
+	keys, _, _ := cache.GetAll(ctx)
+	if len(keys) != 0 {
+		t.Fatalf("expected negative entries to be skipped, got [%d] keys", len(keys))
+	}
+
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (interface{}, time.Duration, error) {
+		calls.Add(1)
+		return nil, 1 * time.Hour, errors.New(lru.ErrKeyNotFound)
+	}
+
+	entry, err := cache.GetOrLoad(ctx, "key1", loader)
+	if err == nil || err.Error() != lru.ErrKeyNegative || !entry.Negative {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNegative, err)
+	}
+
+	_, err = cache.GetOrLoad(ctx, "key2", loader)
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+	_, err = cache.GetOrLoad(ctx, "key2", loader)
+	if err == nil || err.Error() != lru.ErrKeyNegative {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNegative, err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 loader call, got [%d]", calls.Load())
+	}
+
+	err = cache.Put(ctx, "key2", "value2", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data with error [%s]", err.Error())
+	}
+	value, _, err := cache.Get(ctx, "key2")
+	if err != nil || value != "value2" {
+		t.Fatalf("expected [%s], got [%v] with error [%v]", "value2", value, err)
+	}
+}
+
+// тест на отдельный TTL по умолчанию для записей об отсутствии ключа
+func TestNegativeTTL(t *testing.T) {
+	cache := lru.NewLRUCache(10, lru.WithDefaultTTL(1*time.Hour), lru.WithNegativeTTL(1*time.Minute))
+	ctx := context.TODO()
+
+	cache.Put(ctx, "key1", "value1", 0)
+	cache.PutNegative(ctx, "key2", 0)
+
+	entry, err := cache.GetEntry(ctx, "key1")
+	if err != nil || time.Until(entry.ExpiresAt) <= 1*time.Minute {
+		t.Fatalf("expected default ttl for value, got expiration [%s] with error [%v]", entry.ExpiresAt, err)
+	}
+	entry, err = cache.GetEntry(ctx, "key2")
+	if err == nil || err.Error() != lru.ErrKeyNegative || time.Until(entry.ExpiresAt) > 1*time.Minute {
+		t.Fatalf("expected negative ttl for not found entry, got expiration [%s] with error [%v]", entry.ExpiresAt, err)
+	}
+}
@@ -3,6 +3,12 @@ package test
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -180,6 +186,38 @@ func TestGetAll(t *testing.T) {
 	}
 }
 
+// тест на удаление истёкших записей при получении всех данных
+func TestGetAllRemovesExpired(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	reasons := map[string]lru.EvictionReason{}
+	cache.OnEvict(func(key string, value interface{}, reason lru.EvictionReason) {
+		reasons[key] = reason
+	})
+
+	cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	cache.Put(ctx, "key2", "value2", 1*time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	keys, _, err := cache.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all with error [%s]", err.Error())
+	}
+
+	if len(keys) != 1 || keys[0] != "key1" {
+		t.Fatalf("expected only key [key1], got [%v]", keys)
+	}
+
+	if cache.Len() != 1 {
+		t.Fatalf("expected expired entry to be removed, got len [%d]", cache.Len())
+	}
+
+	if reasons["key2"] != lru.EvictionReasonExpired {
+		t.Fatalf("expected reason [%s] for key [%s], got [%s]", lru.EvictionReasonExpired, "key2", reasons["key2"])
+	}
+}
+
 // тест на вместимость кеша
 func TestLRUCapacity(t *testing.T) {
 	cache := lru.NewLRUCache(2)
@@ -226,3 +264,694 @@ func TestLRUCapacity(t *testing.T) {
 		t.Fatalf("failed to get data with error [%s]", err.Error())
 	}
 }
+
+// тест на обобщённый кеш с нестроковыми ключами и типизированными значениями
+func TestGenericCache(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+
+	cache := lru.New[point, int](2)
+	ctx := context.TODO()
+
+	var _ lru.ICache[point, int] = cache
+
+	err := cache.Put(ctx, point{1, 2}, 12, 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data with error [%s]", err.Error())
+	}
+
+	value, _, err := cache.Get(ctx, point{1, 2})
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+	if value != 12 {
+		t.Fatalf("expected [%d], got [%d]", 12, value)
+	}
+
+	value, _, err = cache.Get(ctx, point{2, 1})
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%s]", lru.ErrKeyNotFound, err.Error())
+	}
+	if value != 0 {
+		t.Fatalf("expected zero value, got [%d]", value)
+	}
+}
+
+// тест на фоновую очистку просроченных данных
+func TestJanitor(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	err := cache.Put(ctx, "short", "value1", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to put data1 with error [%s]", err.Error())
+	}
+	err = cache.Put(ctx, "long", "value2", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data2 with error [%s]", err.Error())
+	}
+
+	cache.StartJanitor(50 * time.Millisecond)
+	defer cache.StopJanitor()
+
+	time.Sleep(300 * time.Millisecond)
+
+	if deleted := cache.DeleteExpired(); deleted != 0 {
+		t.Fatalf("expected janitor to delete expired data, [%d] left", deleted)
+	}
+
+	_, _, err = cache.Get(ctx, "short")
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+	_, _, err = cache.Get(ctx, "long")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+}
+
+// тест на обработчики удаления записей с указанием причины
+func TestOnEvict(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	reasons := map[string]lru.EvictionReason{}
+	cache.OnEvict(func(key string, value interface{}, reason lru.EvictionReason) {
+		reasons[key] = reason
+		// обработчик вызывается вне мьютекса и может обращаться к кешу
+		cache.GetAll(ctx)
+	})
+
+	cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	cache.Put(ctx, "key2", "value2", 1*time.Millisecond)
+	cache.Put(ctx, "key3", "value3", 1*time.Hour)
+	cache.Put(ctx, "key4", "value4", 1*time.Hour)
+
+	time.Sleep(5 * time.Millisecond)
+	cache.Get(ctx, "key2")
+	cache.Evict(ctx, "key3")
+	cache.EvictAll(ctx)
+
+	expected := map[string]lru.EvictionReason{
+		"key1": lru.EvictionReasonCapacity,
+		"key2": lru.EvictionReasonCapacity,
+		"key3": lru.EvictionReasonManual,
+		"key4": lru.EvictionReasonCleared,
+	}
+	for key, reason := range expected {
+		if reasons[key] != reason {
+			t.Fatalf("expected reason [%s] for key [%s], got [%s]", reason, key, reasons[key])
+		}
+	}
+
+	cache.Put(ctx, "key5", "value5", 1*time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cache.Get(ctx, "key5")
+	if reasons["key5"] != lru.EvictionReasonExpired {
+		t.Fatalf("expected reason [%s] for key [%s], got [%s]", lru.EvictionReasonExpired, "key5", reasons["key5"])
+	}
+}
+
+// тест на ограничение кеша по суммарному весу записей
+func TestWeightedCapacity(t *testing.T) {
+	cache := lru.NewWeightedLRUCache(0, 10)
+	ctx := context.TODO()
+
+	// "abc" в JSON занимает 5 байт
+	err := cache.Put(ctx, "key1", "abc", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data1 with error [%s]", err.Error())
+	}
+	err = cache.Put(ctx, "key2", "abc", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data2 with error [%s]", err.Error())
+	}
+	err = cache.Put(ctx, "key3", "abcdef", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data3 with error [%s]", err.Error())
+	}
+
+	_, _, err = cache.Get(ctx, "key1")
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+	_, _, err = cache.Get(ctx, "key2")
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+	_, _, err = cache.Get(ctx, "key3")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+
+	err = cache.Put(ctx, "key4", "abcdefghijklmn", 1*time.Hour)
+	if err == nil || err.Error() != lru.ErrEntryTooLarge {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrEntryTooLarge, err)
+	}
+	_, _, err = cache.Get(ctx, "key3")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+}
+
+// тест на применение ограничения веса к записям, добавленным до него
+func TestSetMaxCost(t *testing.T) {
+	cache := lru.NewLRUCache(0)
+	ctx := context.TODO()
+
+	evicted := 0
+	cache.OnEvict(func(key string, value interface{}, reason lru.EvictionReason) {
+		evicted++
+	})
+
+	// "abcdefgh" в JSON занимает 10 байт
+	for i := 0; i < 5; i++ {
+		cache.Put(ctx, fmt.Sprintf("key%d", i), "abcdefgh", 1*time.Hour)
+	}
+	cache.SetMaxCost(20, nil)
+	if cache.Len() != 2 || evicted != 3 {
+		t.Fatalf("expected 2 entries and 3 evictions, got [%d] entries and [%d] evictions", cache.Len(), evicted)
+	}
+
+	cache.Put(ctx, "key5", "abcdefgh", 1*time.Hour)
+	cache.Put(ctx, "key6", "abcdefgh", 1*time.Hour)
+	keys := cache.Keys(ctx)
+	if len(keys) != 2 || keys[0] != "key6" || keys[1] != "key5" {
+		t.Fatalf("expected keys [key6 key5], got %v", keys)
+	}
+}
+
+// тест на статистику обращений к кешу
+func TestStats(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	cache.Put(ctx, "key2", "value2", 1*time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	cache.Get(ctx, "key1")
+	cache.Get(ctx, "key2")
+	cache.Get(ctx, "key3")
+	cache.Put(ctx, "key4", "value4", 1*time.Hour)
+	cache.Put(ctx, "key5", "value5", 1*time.Hour)
+	cache.Evict(ctx, "key5")
+
+	stats := cache.Stats()
+	expected := lru.Stats{
+		Hits:              1,
+		Misses:            2,
+		Expired:           1,
+		CapacityEvictions: 1,
+		ManualEvictions:   1,
+		Len:               1,
+		Capacity:          2,
+	}
+	if stats != expected {
+		t.Fatalf("expected stats [%+v], got [%+v]", expected, stats)
+	}
+	if ratio := stats.HitRatio(); ratio < 0.33 || ratio > 0.34 {
+		t.Fatalf("expected hit ratio about 0.33, got [%f]", ratio)
+	}
+
+	cache.ResetStats()
+	stats = cache.Stats()
+	if stats.Hits != 0 || stats.Misses != 0 || stats.Len != 1 {
+		t.Fatalf("expected reset counters, got [%+v]", stats)
+	}
+}
+
+// тест на чтение без изменения порядка вытеснения
+func TestInspection(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	cache.Put(ctx, "key2", "value2", 1*time.Hour)
+
+	value, _, err := cache.Peek(ctx, "key1")
+	if err != nil {
+		t.Fatalf("failed to peek data with error [%s]", err.Error())
+	}
+	if value != "value1" {
+		t.Fatalf("expected [%s], got [%v]", "value1", value)
+	}
+	if !cache.Contains(ctx, "key1") || cache.Contains(ctx, "key3") {
+		t.Fatal("unexpected contains result")
+	}
+
+	keys := cache.Keys(ctx)
+	if len(keys) != 2 || keys[0] != "key2" || keys[1] != "key1" {
+		t.Fatalf("expected keys [key2 key1], got %v", keys)
+	}
+	if cache.Len() != 2 || cache.Capacity() != 2 {
+		t.Fatalf("expected len and capacity 2, got [%d] and [%d]", cache.Len(), cache.Capacity())
+	}
+
+	// Peek не продвигает key1, поэтому вытесняется именно он
+	cache.Put(ctx, "key3", "value3", 1*time.Hour)
+	if cache.Contains(ctx, "key1") {
+		t.Fatal("expected key1 to be evicted")
+	}
+	if stats := cache.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Fatalf("expected inspection not to affect stats, got [%+v]", stats)
+	}
+}
+
+// тест на обход записей кеша
+func TestAll(t *testing.T) {
+	cache := lru.NewLRUCache(3)
+	ctx := context.TODO()
+
+	cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	cache.Put(ctx, "key2", "value2", 1*time.Millisecond)
+	cache.Put(ctx, "key3", "value3", 1*time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	keys := []string{}
+	for key, entry := range cache.All(lru.IterateSnapshot) {
+		if entry.Value != "value"+key[3:] {
+			t.Fatalf("unexpected value [%v] for key [%s]", entry.Value, key)
+		}
+		keys = append(keys, fmt.Sprintf("%s:%d", key, entry.Rank))
+		// изменения после начала обхода не видны в снимке
+		cache.Evict(ctx, "key1")
+	}
+	if len(keys) != 2 || keys[0] != "key3:0" || keys[1] != "key1:2" {
+		t.Fatalf("expected [key3:0 key1:2], got %v", keys)
+	}
+
+	cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	cache.Put(ctx, "key4", "value4", 1*time.Hour)
+	keys = []string{}
+	for key, entry := range cache.All(lru.IterateLive) {
+		keys = append(keys, fmt.Sprintf("%s:%d", key, entry.Rank))
+		// живой обход не держит мьютекс и пропускает удалённые записи
+		cache.Evict(ctx, "key1")
+	}
+	if len(keys) != 2 || keys[0] != "key4:0" || keys[1] != "key3:2" {
+		t.Fatalf("expected [key4:0 key3:2], got %v", keys)
+	}
+}
+
+// тест на условную запись по версии
+func TestCompareAndSwap(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	version, err := cache.PutIfAbsent(ctx, "key1", "value1", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data with error [%s]", err.Error())
+	}
+	_, err = cache.PutIfAbsent(ctx, "key1", "value2", 1*time.Hour)
+	if err == nil || err.Error() != lru.ErrKeyExists {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyExists, err)
+	}
+	_, err = cache.Replace(ctx, "key2", "value2", 1*time.Hour)
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+
+	entry, _ := cache.GetEntry(ctx, "key1")
+	if entry.Version != version {
+		t.Fatalf("expected version [%d], got [%d]", version, entry.Version)
+	}
+
+	swapped, err := cache.CompareAndSwap(ctx, "key1", version, "value2", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to swap data with error [%s]", err.Error())
+	}
+	if swapped <= version {
+		t.Fatalf("expected version to grow from [%d], got [%d]", version, swapped)
+	}
+	_, err = cache.CompareAndSwap(ctx, "key1", version, "value3", 1*time.Hour)
+	if err == nil || err.Error() != lru.ErrVersionMismatch {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrVersionMismatch, err)
+	}
+
+	value, _, _ := cache.Get(ctx, "key1")
+	if value != "value2" {
+		t.Fatalf("expected [%s], got [%v]", "value2", value)
+	}
+}
+
+// тест на операции с условием на версию записи
+func TestConditionalOperations(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	stale, err := cache.PutIf(ctx, "key1", "value1", 1*time.Hour, lru.Precondition{})
+	if err != nil {
+		t.Fatalf("failed to put data with error [%s]", err.Error())
+	}
+	version, err := cache.PutIf(ctx, "key1", "value2", 1*time.Hour, lru.Precondition{Version: stale})
+	if err != nil || version <= stale {
+		t.Fatalf("expected version to grow from [%d], got [%d] with error [%v]", stale, version, err)
+	}
+
+	// устаревшая версия не даёт изменить или удалить запись
+	if _, err := cache.EvictIf(ctx, "key1", lru.Precondition{Version: stale}); err == nil || err.Error() != lru.ErrPrecondition {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrPrecondition, err)
+	}
+	if err := cache.TouchIf(ctx, "key1", 1*time.Minute, lru.Precondition{Absent: true}); err == nil || err.Error() != lru.ErrPrecondition {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrPrecondition, err)
+	}
+	if err := cache.ExpireAtIf(ctx, "key1", time.Time{}, lru.Precondition{Version: version}); err != nil {
+		t.Fatalf("failed to persist data with error [%s]", err.Error())
+	}
+	value, err := cache.EvictIf(ctx, "key1", lru.Precondition{Version: version})
+	if err != nil || value != "value2" {
+		t.Fatalf("expected [%s], got [%v] with error [%v]", "value2", value, err)
+	}
+
+	// счётчик создаётся только при отсутствии ключа
+	if counter, err := cache.IncrIf(ctx, "counter", 1, 1*time.Hour, lru.Precondition{Absent: true}); err != nil || counter != 1 {
+		t.Fatalf("expected [1], got [%d] with error [%v]", counter, err)
+	}
+	if _, err := cache.IncrIf(ctx, "counter", 1, 1*time.Hour, lru.Precondition{Absent: true}); err == nil || err.Error() != lru.ErrPrecondition {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrPrecondition, err)
+	}
+	if _, err := cache.EvictIf(ctx, "missing", lru.Precondition{Exists: true}); err == nil || err.Error() != lru.ErrPrecondition {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrPrecondition, err)
+	}
+}
+
+// тест на атомарное изменение значения при конкурентной записи
+func TestUpdate(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	increment := func(old interface{}, exists bool) (interface{}, time.Duration, bool) {
+		if !exists {
+			return 1, 1 * time.Hour, false
+		}
+		return old.(int) + 1, 1 * time.Hour, false
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Update(ctx, "counter", increment)
+		}()
+	}
+	wg.Wait()
+
+	value, _, err := cache.Get(ctx, "counter")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+	if value != 50 {
+		t.Fatalf("expected [50], got [%v]", value)
+	}
+
+	// изменение значения сохраняет теги записи
+	cache.PutWithOptions(ctx, "tagged", 1, 1*time.Hour, lru.WithTags("group"))
+	cache.Update(ctx, "tagged", increment)
+	if evicted := cache.EvictByTag(ctx, "group"); evicted != 1 {
+		t.Fatalf("expected 1 evicted entry, got [%d]", evicted)
+	}
+
+	cache.Update(ctx, "counter", func(old interface{}, exists bool) (interface{}, time.Duration, bool) {
+		return nil, 0, true
+	})
+	if cache.Contains(ctx, "counter") {
+		t.Fatal("expected counter to be removed")
+	}
+}
+
+// тест на целочисленные счётчики
+func TestIncr(t *testing.T) {
+	cache := lru.NewLRUCache(2)
+	ctx := context.TODO()
+
+	value, err := cache.Incr(ctx, "counter", 5, 1*time.Hour)
+	if err != nil || value != 5 {
+		t.Fatalf("expected [5], got [%d] with error [%v]", value, err)
+	}
+	_, expiresAt, _ := cache.Get(ctx, "counter")
+
+	// значение из JSON хранится как float64
+	cache.Put(ctx, "json", float64(10), 1*time.Hour)
+	value, err = cache.Decr(ctx, "json", 3, 1*time.Hour)
+	if err != nil || value != 7 {
+		t.Fatalf("expected [7], got [%d] with error [%v]", value, err)
+	}
+
+	value, err = cache.Incr(ctx, "counter", 1, 1*time.Minute)
+	if err != nil || value != 6 {
+		t.Fatalf("expected [6], got [%d] with error [%v]", value, err)
+	}
+	_, newExpiresAt, _ := cache.Get(ctx, "counter")
+	if !newExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expected expiration [%s] to be kept, got [%s]", expiresAt, newExpiresAt)
+	}
+
+	cache.Put(ctx, "json", "text", 1*time.Hour)
+	_, err = cache.Incr(ctx, "json", 1, 1*time.Hour)
+	if err == nil || err.Error() != lru.ErrNotNumeric {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrNotNumeric, err)
+	}
+
+	cache.Put(ctx, "counter", int64(math.MaxInt64), 1*time.Hour)
+	_, err = cache.Incr(ctx, "counter", 1, 1*time.Hour)
+	if err == nil || err.Error() != lru.ErrOverflow {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrOverflow, err)
+	}
+}
+
+// тест на удаление записей по тегу
+func TestEvictByTag(t *testing.T) {
+	cache := lru.NewLRUCache(4)
+	ctx := context.TODO()
+
+	cache.PutWithOptions(ctx, "user:42:profile", "profile", 1*time.Hour, lru.WithTags("user:42"))
+	cache.PutWithOptions(ctx, "user:42:orders", "orders", 1*time.Hour, lru.WithTags("user:42", "orders"))
+	cache.PutWithOptions(ctx, "user:7:orders", "orders", 1*time.Hour, lru.WithTags("user:7", "orders"))
+	// повторная запись без тегов исключает ключ из группы
+	cache.PutWithOptions(ctx, "user:42:settings", "settings", 1*time.Hour, lru.WithTags("user:42"))
+	cache.Put(ctx, "user:42:settings", "settings", 1*time.Hour)
+
+	if evicted := cache.EvictByTag(ctx, "user:42"); evicted != 2 {
+		t.Fatalf("expected 2 evicted entries, got [%d]", evicted)
+	}
+	keys := cache.Keys(ctx)
+	if len(keys) != 2 || keys[0] != "user:42:settings" || keys[1] != "user:7:orders" {
+		t.Fatalf("expected keys [user:42:settings user:7:orders], got %v", keys)
+	}
+	if evicted := cache.EvictByTag(ctx, "orders"); evicted != 1 {
+		t.Fatalf("expected 1 evicted entry, got [%d]", evicted)
+	}
+}
+
+// тест на выборку и удаление записей по префиксу ключа
+func TestKeysWithPrefix(t *testing.T) {
+	cache := lru.NewLRUCache(0)
+	ctx := context.TODO()
+
+	// набор ключей с общими префиксами, часть удаляется, результат сверяется с полным перебором
+	rnd := rand.New(rand.NewPCG(1, 2))
+	keys := map[string]bool{}
+	for range 2000 {
+		key := fmt.Sprintf("user:%d:%s", rnd.IntN(30), []string{"", "p", "profile", "pro", "orders"}[rnd.IntN(5)])
+		if rnd.IntN(3) == 0 {
+			cache.Evict(ctx, key)
+			delete(keys, key)
+			continue
+		}
+		cache.Put(ctx, key, key, 1*time.Hour)
+		keys[key] = true
+	}
+
+	for _, prefix := range []string{"", "user:1", "user:1:", "user:12:pro", "user:7:profile", "user:99", "x"} {
+		expected := []string{}
+		for key := range keys {
+			if strings.HasPrefix(key, prefix) {
+				expected = append(expected, key)
+			}
+		}
+		slices.Sort(expected)
+
+		got := cache.KeysWithPrefix(ctx, prefix)
+		if !slices.Equal(got, expected) {
+			t.Fatalf("unexpected keys for prefix [%s]: expected %v, got %v", prefix, expected, got)
+		}
+	}
+
+	evicted := cache.EvictPrefix(ctx, "user:1")
+	if len(cache.KeysWithPrefix(ctx, "user:1")) != 0 || cache.Len() != len(keys)-evicted {
+		t.Fatalf("expected prefix [user:1] to be evicted, got len [%d]", cache.Len())
+	}
+}
+
+// тест на скользящее истечение с предельным временем жизни
+func TestSlidingExpiration(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	cache := lru.NewLRUCache(2, lru.WithClock(clock))
+	ctx := context.TODO()
+
+	cache.PutWithOptions(ctx, "session", "value", 60*time.Second, lru.WithSliding(100*time.Second))
+	cache.Put(ctx, "fixed", "value", 30*time.Second)
+
+	// чтения чаще TTL продлевают запись, пока не истечёт предельное время жизни
+	for range 4 {
+		clock.Advance(20 * time.Second)
+		if _, _, err := cache.Get(ctx, "session"); err != nil {
+			t.Fatalf("failed to get sliding entry with error [%s]", err.Error())
+		}
+	}
+	if _, _, err := cache.Get(ctx, "fixed"); err == nil {
+		t.Fatal("expected fixed entry to expire")
+	}
+
+	clock.Advance(30 * time.Second)
+	_, _, err := cache.Get(ctx, "session")
+	if err == nil || err.Error() != lru.ErrKeyExpired {
+		t.Fatalf("expected [%s] after max lifetime, got [%v]", lru.ErrKeyExpired, err)
+	}
+}
+
+// тест на истечение записи по времени простоя
+func TestTimeToIdle(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	cache := lru.NewLRUCache(3, lru.WithClock(clock))
+	ctx := context.TODO()
+
+	cache.PutWithOptions(ctx, "read", "value", 1*time.Hour, lru.WithTTI(60*time.Second))
+	cache.PutWithOptions(ctx, "idle", "value", 1*time.Hour, lru.WithTTI(60*time.Second))
+
+	// чтения чаще времени простоя сохраняют запись
+	for range 3 {
+		clock.Advance(30 * time.Second)
+		if _, _, err := cache.Get(ctx, "read"); err != nil {
+			t.Fatalf("failed to get read entry with error [%s]", err.Error())
+		}
+	}
+
+	keys, _, _ := cache.GetAll(ctx)
+	if len(keys) != 1 || keys[0] != "read" {
+		t.Fatalf("expected keys [read], got %v", keys)
+	}
+	// просроченная по простою запись удаляется при получении всех данных
+	if cache.Len() != 1 {
+		t.Fatalf("expected idle entry to be removed, got len [%d]", cache.Len())
+	}
+	if _, _, err := cache.Get(ctx, "idle"); err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+}
+
+// тест на управление сроком действия записей
+func TestTTLManagement(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	cache := lru.NewLRUCache(2, lru.WithClock(clock))
+	ctx := context.TODO()
+
+	cache.Put(ctx, "key1", "value1", 20*time.Second)
+	cache.Put(ctx, "key2", "value2", lru.NoExpiration)
+
+	ttl, err := cache.TTL(ctx, "key2")
+	if err != nil || ttl != lru.NoExpiration {
+		t.Fatalf("expected [%s], got [%s] with error [%v]", lru.NoExpiration, ttl, err)
+	}
+	if err := cache.Touch(ctx, "key1", 1*time.Hour); err != nil {
+		t.Fatalf("failed to touch key with error [%s]", err.Error())
+	}
+	if err := cache.Persist(ctx, "key1"); err != nil {
+		t.Fatalf("failed to persist key with error [%s]", err.Error())
+	}
+	if err := cache.ExpireAt(ctx, "key2", clock.Now().Add(20*time.Second)); err != nil {
+		t.Fatalf("failed to set expiration with error [%s]", err.Error())
+	}
+
+	clock.Advance(30 * time.Second)
+	value, expiresAt, err := cache.Get(ctx, "key1")
+	if err != nil || value != "value1" || !expiresAt.IsZero() {
+		t.Fatalf("expected persistent [value1], got [%v] expiring at [%s] with error [%v]", value, expiresAt, err)
+	}
+	if deleted := cache.DeleteExpired(); deleted != 1 {
+		t.Fatalf("expected 1 deleted entry, got [%d]", deleted)
+	}
+	if _, err := cache.TTL(ctx, "key2"); err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+
+	// предельное время жизни скользящей записи ограничивает новый срок
+	cache.PutWithOptions(ctx, "sliding", "value", 10*time.Second, lru.WithSliding(1*time.Minute))
+	if err := cache.Touch(ctx, "sliding", 1*time.Hour); err != nil {
+		t.Fatalf("failed to touch key with error [%s]", err.Error())
+	}
+	if ttl, _ := cache.TTL(ctx, "sliding"); ttl <= 0 || ttl > 1*time.Minute {
+		t.Fatalf("expected ttl limited by [%s], got [%s]", 1*time.Minute, ttl)
+	}
+	if err := cache.Persist(ctx, "sliding"); err != nil {
+		t.Fatalf("failed to persist key with error [%s]", err.Error())
+	}
+	if ttl, _ := cache.TTL(ctx, "sliding"); ttl <= 0 || ttl > 1*time.Minute {
+		t.Fatalf("expected ttl limited by [%s], got [%s]", 1*time.Minute, ttl)
+	}
+}
+
+// часы с ручным управлением временем
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// тест на настройки сроков действия при создании кеша
+func TestCacheOptions(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	evicted := []string{}
+	cache := lru.NewLRUCache(10,
+		lru.WithDefaultTTL(1*time.Minute),
+		lru.WithMaxTTL(1*time.Hour),
+		lru.WithTTLJitter(0.5),
+		lru.WithClock(clock),
+		lru.WithEvictionListener(func(key string, value interface{}, reason lru.EvictionReason) {
+			evicted = append(evicted, key)
+		}),
+	)
+	ctx := context.TODO()
+
+	cache.Put(ctx, "default", "value", 0)
+	cache.Put(ctx, "long", "value", 24*time.Hour)
+	cache.Put(ctx, "persistent", "value", lru.NoExpiration)
+
+	// с долей разброса 0.5 TTL лежит в пределах [ttl/2, ttl), разброс применяется и к TTL,
+	// ограниченному максимальным, чтобы такие записи не истекали одновременно
+	for key, ttl := range map[string]time.Duration{"default": 1 * time.Minute, "long": 1 * time.Hour, "persistent": 1 * time.Hour} {
+		got, err := cache.TTL(ctx, key)
+		if err != nil || got >= ttl || got < ttl/2 {
+			t.Fatalf("expected ttl of [%s] within [%s, %s), got [%s] with error [%v]", key, ttl/2, ttl, got, err)
+		}
+	}
+
+	clock.Advance(1 * time.Minute)
+	if _, _, err := cache.Get(ctx, "default"); err == nil {
+		t.Fatal("expected default ttl entry to expire")
+	}
+	clock.Advance(1 * time.Hour)
+	if deleted := cache.DeleteExpired(); deleted != 2 {
+		t.Fatalf("expected 2 deleted entries, got [%d]", deleted)
+	}
+	if len(evicted) != 3 {
+		t.Fatalf("expected 3 eviction events, got %v", evicted)
+	}
+}
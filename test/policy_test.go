@@ -0,0 +1,188 @@
+// пакет тестов
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"lru"
+)
+
+// тест на вытеснение наименее часто используемого ключа в LFU-кеше
+func TestLFUEviction(t *testing.T) {
+	cache := lru.NewLFUCache(2)
+	ctx := context.TODO()
+
+	err := cache.Put(ctx, "key1", "value1", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data1 with error [%s]", err.Error())
+	}
+	err = cache.Put(ctx, "key2", "value2", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data2 with error [%s]", err.Error())
+	}
+
+	// key1 используется чаще, хотя key2 использовался последним
+	cache.Get(ctx, "key1")
+	cache.Get(ctx, "key1")
+	cache.Get(ctx, "key2")
+
+	err = cache.Put(ctx, "key3", "value3", 1*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to put data3 with error [%s]", err.Error())
+	}
+
+	_, _, err = cache.Get(ctx, "key2")
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+	_, _, err = cache.Get(ctx, "key1")
+	if err != nil {
+		t.Fatalf("failed to get data1 with error [%s]", err.Error())
+	}
+
+	keys, _, _ := cache.GetAll(ctx)
+	expectedKeys := []string{"key1", "key3"}
+	if len(keys) != len(expectedKeys) {
+		t.Fatalf("expected [%d] keys, got [%d]", len(expectedKeys), len(keys))
+	}
+	for i, key := range keys {
+		if key != expectedKeys[i] {
+			t.Fatalf("expected key [%s], got [%s]", expectedKeys[i], key)
+		}
+	}
+}
+
+// тест на создание стратегии по названию
+func TestNewPolicy(t *testing.T) {
+	for _, name := range []string{lru.PolicyLRU, lru.PolicyLFU, lru.PolicyARC, lru.PolicyTinyLFU} {
+		if _, err := lru.NewPolicy[string](name, 10); err != nil {
+			t.Fatalf("failed to create policy [%s] with error [%s]", name, err.Error())
+		}
+	}
+	if _, err := lru.NewPolicy[string]("unknown", 10); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+// тест на устойчивость ARC-кеша к сканированию
+func TestARCScanResistance(t *testing.T) {
+	var cache lru.ILRUCache = lru.NewARCCache(4)
+	ctx := context.TODO()
+
+	for _, key := range []string{"hot1", "hot2"} {
+		err := cache.Put(ctx, key, key, 1*time.Hour)
+		if err != nil {
+			t.Fatalf("failed to put data with error [%s]", err.Error())
+		}
+		cache.Get(ctx, key)
+	}
+
+	// однократное чтение множества новых ключей не должно вытеснить повторно используемые
+	for i := 0; i < 20; i++ {
+		err := cache.Put(ctx, fmt.Sprintf("scan%d", i), i, 1*time.Hour)
+		if err != nil {
+			t.Fatalf("failed to put data with error [%s]", err.Error())
+		}
+	}
+
+	for _, key := range []string{"hot1", "hot2"} {
+		value, _, err := cache.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("failed to get data by key [%s] with error [%s]", key, err.Error())
+		}
+		if value != key {
+			t.Fatalf("expected [%s], got [%v]", key, value)
+		}
+	}
+
+	keys, _, _ := cache.GetAll(ctx)
+	if len(keys) != 4 {
+		t.Fatalf("expected 4 keys, got [%d]", len(keys))
+	}
+}
+
+// тест на истечение срока действия в ARC-кеше
+func TestARCExpiration(t *testing.T) {
+	cache := lru.NewARCCache(2)
+	ctx := context.TODO()
+
+	err := cache.Put(ctx, "key1", "value1", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to put data with error [%s]", err.Error())
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	_, _, err = cache.Get(ctx, "key1")
+	if err == nil || err.Error() != lru.ErrKeyExpired {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyExpired, err)
+	}
+}
+
+// тест на фильтр допуска W-TinyLFU: редкие новые ключи не вытесняют часто используемые
+func TestTinyLFUAdmission(t *testing.T) {
+	var cache lru.ILRUCache = lru.NewTinyLFUCache(10)
+	ctx := context.TODO()
+
+	// счётчики горячих ключей доводятся до максимума sketch: ключ из сканирования допускается, только если
+	// его оценка строго больше оценки жертвы, поэтому результат не зависит от случайных коллизий хешей
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("hot%d", i)
+		err := cache.Put(ctx, key, i, 1*time.Hour)
+		if err != nil {
+			t.Fatalf("failed to put data with error [%s]", err.Error())
+		}
+		for j := 0; j < 15; j++ {
+			cache.Get(ctx, key)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		err := cache.Put(ctx, fmt.Sprintf("scan%d", i), i, 1*time.Hour)
+		if err != nil {
+			t.Fatalf("failed to put data with error [%s]", err.Error())
+		}
+	}
+
+	hits := 0
+	for i := 0; i < 10; i++ {
+		if _, _, err := cache.Get(ctx, fmt.Sprintf("hot%d", i)); err == nil {
+			hits++
+		}
+	}
+	// последний горячий ключ находится в окне и вытесняется первым ключом сканирования
+	if hits != 9 {
+		t.Fatalf("expected 9 hot keys to survive the scan, got [%d]", hits)
+	}
+
+	keys, _, _ := cache.GetAll(ctx)
+	if len(keys) != 10 {
+		t.Fatalf("expected 10 keys, got [%d]", len(keys))
+	}
+}
+
+// тест на учёт промахов в частоте ключей фильтра допуска W-TinyLFU
+func TestTinyLFUMissFrequency(t *testing.T) {
+	cache := lru.NewTinyLFUCache(10)
+	ctx := context.TODO()
+
+	for i := 0; i < 10; i++ {
+		cache.Put(ctx, fmt.Sprintf("cold%d", i), i, 1*time.Hour)
+	}
+
+	// ключ, который часто запрашивают в его отсутствие, набирает частоту до записи
+	for i := 0; i < 5; i++ {
+		if _, _, err := cache.Get(ctx, "wanted"); err == nil || err.Error() != lru.ErrKeyNotFound {
+			t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+		}
+	}
+	cache.Put(ctx, "wanted", "value", 1*time.Hour)
+	// вытеснение из окна проводит ключ через фильтр допуска
+	cache.Put(ctx, "next", "value", 1*time.Hour)
+
+	if !cache.Contains(ctx, "wanted") {
+		t.Fatal("expected frequently missed key to be admitted")
+	}
+}
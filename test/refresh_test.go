@@ -0,0 +1,133 @@
+// пакет тестов
+package test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lru"
+)
+
+// тест на упреждающее обновление записи в конце её TTL
+func TestRefreshAhead(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	var calls atomic.Int32
+	cache.SetRefreshAhead("users", lru.RefreshRule[string, interface{}]{
+		Match: func(key string) bool {
+			return strings.HasPrefix(key, "user:")
+		},
+		Loader: func(ctx context.Context, key string) (interface{}, time.Duration, error) {
+			calls.Add(1)
+			return "refreshed", 1 * time.Hour, nil
+		},
+		Threshold: 0.5,
+	})
+
+	cache.Put(ctx, "user:1", "initial", 100*time.Millisecond)
+	cache.Put(ctx, "other", "initial", 100*time.Millisecond)
+
+	// до порога обновление не запускается
+	cache.Get(ctx, "user:1")
+	time.Sleep(60 * time.Millisecond)
+
+	// в пороге возвращается текущее значение и запускается одна фоновая загрузка
+	for i := 0; i < 5; i++ {
+		value, _, err := cache.Get(ctx, "user:1")
+		if err != nil {
+			t.Fatalf("failed to get data with error [%s]", err.Error())
+		}
+		if value != "initial" && value != "refreshed" {
+			t.Fatalf("unexpected value [%v]", value)
+		}
+	}
+	cache.Get(ctx, "other")
+
+	time.Sleep(100 * time.Millisecond)
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 loader call, got [%d]", calls.Load())
+	}
+	value, expiresAt, err := cache.Get(ctx, "user:1")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+	if value != "refreshed" || time.Until(expiresAt) < 59*time.Minute {
+		t.Fatalf("expected refreshed value with new TTL, got [%v] expiring at [%v]", value, expiresAt)
+	}
+	_, _, err = cache.Get(ctx, "other")
+	if err == nil || err.Error() != lru.ErrKeyExpired {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyExpired, err)
+	}
+
+	if !cache.RemoveRefreshAhead("users") {
+		t.Fatal("expected refresh rule to be removed")
+	}
+}
+
+// тест на отбрасывание результата перезагрузки записи, удалённой или изменённой во время загрузки
+func TestRefreshAheadConcurrentWrite(t *testing.T) {
+	cache := lru.NewLRUCache(10)
+	ctx := context.TODO()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	settled := make(chan struct{})
+	var calls atomic.Int32
+	cache.SetRefreshAhead("all", lru.RefreshRule[string, interface{}]{
+		Loader: func(ctx context.Context, key string) (interface{}, time.Duration, error) {
+			if key == "evicted" {
+				close(started)
+				<-release
+				return "reloaded", 1 * time.Hour, nil
+			}
+			// вторая перезагрузка ключа запускается только после завершения первой
+			if calls.Add(1) == 2 {
+				close(settled)
+				return nil, 0, errors.New("origin unavailable")
+			}
+			<-release
+			return "reloaded", 1 * time.Hour, nil
+		},
+		// любое чтение попадает в порог обновления
+		Threshold: 1,
+	})
+
+	cache.Put(ctx, "evicted", "initial", 1*time.Hour)
+	cache.Get(ctx, "evicted")
+	<-started
+	cache.Evict(ctx, "evicted")
+
+	cache.Put(ctx, "replaced", "initial", 1*time.Hour)
+	cache.Get(ctx, "replaced")
+	cache.Evict(ctx, "replaced")
+	cache.Put(ctx, "replaced", "put", 1*time.Hour)
+	close(release)
+
+	// промах присоединяется к выполняющейся загрузке и дожидается её завершения
+	cache.GetOrLoad(ctx, "evicted", func(ctx context.Context) (interface{}, time.Duration, error) {
+		return nil, 0, errors.New(lru.ErrKeyNotFound)
+	})
+	if cache.Contains(ctx, "evicted") {
+		t.Fatal("expected evicted key not to be restored by reload")
+	}
+
+	for done := false; !done; {
+		select {
+		case <-settled:
+			done = true
+		default:
+		}
+		value, _, err := cache.Get(ctx, "replaced")
+		if err != nil || value != "put" {
+			t.Fatalf("expected [%s], got [%v] with error [%v]", "put", value, err)
+		}
+		runtime.Gosched()
+	}
+}
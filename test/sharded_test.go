@@ -0,0 +1,159 @@
+// пакет тестов
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"lru"
+)
+
+// тест на работу шардированного кеша через интерфейс ILRUCache
+func TestShardedPutGetEvict(t *testing.T) {
+	var cache lru.ILRUCache = lru.NewShardedLRUCache(16, 4)
+	ctx := context.TODO()
+
+	for i := 0; i < 8; i++ {
+		err := cache.Put(ctx, fmt.Sprintf("key%d", i), i, 1*time.Hour)
+		if err != nil {
+			t.Fatalf("failed to put data with error [%s]", err.Error())
+		}
+	}
+
+	value, _, err := cache.Get(ctx, "key3")
+	if err != nil {
+		t.Fatalf("failed to get data with error [%s]", err.Error())
+	}
+	if value != 3 {
+		t.Fatalf("expected [%d], got [%v]", 3, value)
+	}
+
+	keys, values, err := cache.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all with error [%s]", err.Error())
+	}
+	if len(keys) != 8 || len(values) != 8 {
+		t.Fatalf("expected 8 keys and 8 values, got [%d] keys and [%d] values", len(keys), len(values))
+	}
+	for i, key := range keys {
+		if key != fmt.Sprintf("key%d", values[i]) {
+			t.Fatalf("key [%s] does not match value [%v]", key, values[i])
+		}
+	}
+
+	_, err = cache.Evict(ctx, "key3")
+	if err != nil {
+		t.Fatalf("failed to evict data with error [%s]", err.Error())
+	}
+	_, _, err = cache.Get(ctx, "key3")
+	if err == nil || err.Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, err)
+	}
+
+	err = cache.EvictAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to evict all with error [%s]", err.Error())
+	}
+	keys, _, _ = cache.GetAll(ctx)
+	if len(keys) != 0 {
+		t.Fatalf("expected empty cache, got [%d] keys", len(keys))
+	}
+}
+
+// тест на конкурентный доступ к шардированному кешу
+func TestShardedConcurrentAccess(t *testing.T) {
+	cache := lru.NewShardedLRUCache(100, 8)
+	ctx := context.TODO()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprintf("key%d", (g*1000+i)%200)
+				cache.Put(ctx, key, i, 1*time.Hour)
+				cache.Get(ctx, key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	keys, _, err := cache.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all with error [%s]", err.Error())
+	}
+	if len(keys) > 100 {
+		t.Fatalf("expected at most 100 keys, got [%d]", len(keys))
+	}
+}
+
+// тест на пакетные операции с распределением ключей по шардам
+func TestShardedBatch(t *testing.T) {
+	cache := lru.NewShardedLRUCache(10, 3)
+	ctx := context.TODO()
+
+	items := []lru.BatchItem[string, interface{}]{}
+	for i := range 5 {
+		items = append(items, lru.BatchItem[string, interface{}]{Key: fmt.Sprintf("key%d", i), Value: i, TTL: 1 * time.Hour})
+	}
+	for i, err := range cache.PutMany(ctx, items) {
+		if err != nil {
+			t.Fatalf("failed to put item [%d] with error [%s]", i, err.Error())
+		}
+	}
+
+	keys := []string{"key4", "missing", "key0", "key2"}
+	entries, errs := cache.GetMany(ctx, keys)
+	if errs[1] == nil || errs[1].Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s], got [%v]", lru.ErrKeyNotFound, errs[1])
+	}
+	for i, expected := range map[int]int{0: 4, 2: 0, 3: 2} {
+		if errs[i] != nil || entries[i].Value != expected {
+			t.Fatalf("expected [%d] for key [%s], got [%v] with error [%v]", expected, keys[i], entries[i].Value, errs[i])
+		}
+	}
+
+	values, errs := cache.EvictMany(ctx, []string{"key1", "key1"})
+	if errs[0] != nil || values[0] != 1 {
+		t.Fatalf("expected evicted value [1], got [%v] with error [%v]", values[0], errs[0])
+	}
+	if errs[1] == nil || errs[1].Error() != lru.ErrKeyNotFound {
+		t.Fatalf("expected [%s] on repeated evict, got [%v]", lru.ErrKeyNotFound, errs[1])
+	}
+}
+
+// тест на одновременное удаление записей с тегом во всех шардах
+func TestShardedEvictByTag(t *testing.T) {
+	cache := lru.NewShardedLRUCache(16, 4)
+	ctx := context.TODO()
+
+	keys := make([]string, 0)
+	for i := 0; i < 8; i++ {
+		key := fmt.Sprintf("key%d", i)
+		keys = append(keys, key)
+		cache.PutWithOptions(ctx, key, i, 1*time.Hour, lru.WithTags("group"))
+	}
+	cache.Put(ctx, "other", "value", 1*time.Hour)
+
+	// к вызову обработчика удаления ни одной записи группы уже нет ни в одном шарде
+	events := 0
+	cache.OnEvict(func(key string, value interface{}, reason lru.EvictionReason) {
+		events++
+		for _, key := range keys {
+			if cache.Contains(ctx, key) {
+				t.Errorf("expected key [%s] to be evicted with the whole group", key)
+			}
+		}
+	})
+
+	if evicted := cache.EvictByTag(ctx, "group"); evicted != 8 {
+		t.Fatalf("expected 8 evicted entries, got [%d]", evicted)
+	}
+	if events != 8 || cache.Len() != 1 {
+		t.Fatalf("expected 8 eviction events and 1 entry left, got [%d] events and [%d] entries", events, cache.Len())
+	}
+}